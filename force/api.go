@@ -1,8 +1,10 @@
 package force
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"sync"
 )
 
 const (
@@ -19,16 +21,23 @@ const (
 	resourcesUri = "/services/data/%v"
 )
 
+// ForceApi is safe for concurrent use by multiple goroutines.
 type ForceApi struct {
-	apiVersion             string
-	oauth                  *forceOauth
+	apiVersion    string
+	oauth         *forceOauth
+	httpClient    HttpClient
+	defaultHeader http.Header
+	requestErrors bool
+
+	// mu guards the metadata caches, the logger and the retry policy below.
+	mu                     sync.RWMutex
 	apiResources           map[string]string
 	apiSObjects            map[string]*SObjectMetaData
 	apiSObjectDescriptions map[string]*SObjectDescription
 	apiMaxBatchSize        int64
 	logger                 ForceApiLogger
 	logPrefix              string
-	httpClient             HttpClient
+	retryPolicy            *RetryPolicy
 }
 
 type RefreshTokenResponse struct {
@@ -96,7 +105,7 @@ type SObjectDescription struct {
 	Listviewable        bool                 `json:"listviewable"`
 	DeprecatedAndHidden bool                 `json:"deprecatedAndHidden"`
 	RecordTypeInfos     []*RecordTypeInfo    `json:"recordTypeInfos"`
-	ChildRelationsips   []*ChildRelationship `json:"childRelationships"`
+	ChildRelationsips   []*ChildRelationship `json:"childRelationships" force:"childRelationships"`
 
 	AllFields string `json:"-"` // Not from force.com API. Used to generate SELECT * queries.
 }
@@ -197,22 +206,56 @@ type RestAPI interface {
 	UpsertSObjectByExternalId(externalKey string, externalId string, in SObject) (responseCode int, resp *SObjectResponse, err error)
 }
 
-func (forceApi *ForceApi) getApiResources() error {
+// ContextRestAPI mirrors RestAPI with every network call taking a context.Context,
+// so callers can cancel requests or enforce per-call deadlines.
+type ContextRestAPI interface {
+	DeleteSObjectContext(ctx context.Context, id string, in SObject) (err error)
+	DeleteSObjectByExternalIdContext(ctx context.Context, externalKey, externalId string, in SObject) (err error)
+	DescribeSObjectsContext(ctx context.Context) (map[string]*SObjectMetaData, error)
+	DescribeSObjectContext(ctx context.Context, in SObject) (resp *SObjectDescription, err error)
+	HasAccess(objectNames []string) bool
+	InsertSObjectContext(ctx context.Context, in SObject) (resp *SObjectResponse, err error)
+	GetAccessToken() string
+	GetInstanceURL() string
+	GetLimitsContext(ctx context.Context) (*Limits, error)
+	GetSObjectContext(ctx context.Context, id string, fields []string, out SObject) (err error)
+	GetSObjectByExternalIdContext(ctx context.Context, externalKey, externalId string, fields []string, out SObject) (statusCode int, err error)
+	GetSFIDsByExternalIdContext(ctx context.Context, apiName, externalKey, externalId string) ([]string, int, error)
+	QueryContext(ctx context.Context, query string, out interface{}) (err error)
+	QueryAllContext(ctx context.Context, query string, out interface{}) (err error)
+	QueryNextContext(ctx context.Context, uri string, out interface{}) (err error)
+	RefreshTokenContext(ctx context.Context) error
+	UpdateSObjectContext(ctx context.Context, id string, in SObject) (err error)
+	UpsertSObjectByExternalIdContext(ctx context.Context, externalKey string, externalId string, in SObject) (responseCode int, resp *SObjectResponse, err error)
+}
+
+func (forceApi *ForceApi) getApiResources(ctx context.Context) error {
 	uri := fmt.Sprintf(resourcesUri, forceApi.apiVersion)
 
-	_, err := forceApi.Get(uri, nil, &forceApi.apiResources)
-	return err
+	resources := make(map[string]string)
+	if _, err := forceApi.GetContext(ctx, uri, nil, &resources); err != nil {
+		return err
+	}
+
+	forceApi.mu.Lock()
+	forceApi.apiResources = resources
+	forceApi.mu.Unlock()
+
+	return nil
 }
 
-func (forceApi *ForceApi) getApiSObjects() error {
-	uri := forceApi.apiResources[sObjectsKey]
+func (forceApi *ForceApi) getApiSObjects(ctx context.Context) error {
+	uri := forceApi.resourceUrl(sObjectsKey)
 
 	list := &SObjectApiResponse{}
-	_, err := forceApi.Get(uri, nil, list)
+	_, err := forceApi.GetContext(ctx, uri, nil, list)
 	if err != nil {
 		return err
 	}
 
+	forceApi.mu.Lock()
+	defer forceApi.mu.Unlock()
+
 	forceApi.apiMaxBatchSize = list.MaxBatchSize
 
 	// The API doesn't return the list of sobjects in a map. Convert it.
@@ -223,51 +266,139 @@ func (forceApi *ForceApi) getApiSObjects() error {
 	return nil
 }
 
-func (forceApi *ForceApi) getApiSObjectDescriptions() error {
-	for name, metaData := range forceApi.apiSObjects {
+// resourceUrl returns the url of the api resource with the given key. It
+// falls back to the conventional url when the api resources weren't loaded.
+func (forceApi *ForceApi) resourceUrl(key string) string {
+	forceApi.mu.RLock()
+	defer forceApi.mu.RUnlock()
+
+	if uri, ok := forceApi.apiResources[key]; ok {
+		return uri
+	}
+
+	return fmt.Sprintf(resourcesUri+"/%v", forceApi.apiVersion, key)
+}
+
+// sObjectUrl returns the url with the given key, such as sObjectKey or
+// rowTemplateKey, of the sobject named apiName. It falls back to the
+// conventional url when the sobject's metadata wasn't loaded.
+func (forceApi *ForceApi) sObjectUrl(apiName, key string) string {
+	forceApi.mu.RLock()
+	metaData, ok := forceApi.apiSObjects[apiName]
+	forceApi.mu.RUnlock()
+
+	if ok {
+		if uri, ok := metaData.URLs[key]; ok {
+			return uri
+		}
+	}
+
+	uri := fmt.Sprintf("%v/%v", forceApi.resourceUrl(sObjectsKey), apiName)
+	switch key {
+	case rowTemplateKey:
+		return uri + "/" + idKey
+	case sObjectDescribeKey:
+		return uri + "/describe"
+	}
+	return uri
+}
+
+func (forceApi *ForceApi) getApiSObjectDescriptions(ctx context.Context) error {
+	for name, metaData := range forceApi.sObjects() {
 		uri := metaData.URLs[sObjectDescribeKey]
 
 		desc := &SObjectDescription{}
-		_, err := forceApi.Get(uri, nil, desc)
+		_, err := forceApi.GetContext(ctx, uri, nil, desc)
 		if err != nil {
 			return err
 		}
 
+		forceApi.mu.Lock()
 		forceApi.apiSObjectDescriptions[name] = desc
+		forceApi.mu.Unlock()
 	}
 
 	return nil
 }
 
+// sObjects returns a copy of the sobject metadata loaded so far.
+func (forceApi *ForceApi) sObjects() map[string]*SObjectMetaData {
+	forceApi.mu.RLock()
+	defer forceApi.mu.RUnlock()
+
+	sObjects := make(map[string]*SObjectMetaData, len(forceApi.apiSObjects))
+	for name, metaData := range forceApi.apiSObjects {
+		sObjects[name] = metaData
+	}
+
+	return sObjects
+}
+
 func (forceApi *ForceApi) GetInstanceURL() string {
-	return forceApi.oauth.InstanceUrl
+	return forceApi.oauth.token().InstanceUrl
 }
 
 func (forceApi *ForceApi) GetAccessToken() string {
-	return forceApi.oauth.AccessToken
+	return forceApi.oauth.token().AccessToken
+}
+
+// GetIdentityURL returns the identity url of the user the access token was
+// issued to, such as "https://login.salesforce.com/id/00Dxx0000001gPL/005xx000001SwiU",
+// which holds the ids of the organization and the user. It is empty when the
+// token was obtained elsewhere along with no identity url.
+func (forceApi *ForceApi) GetIdentityURL() string {
+	return forceApi.oauth.token().Id
+}
+
+// RenewAccessToken obtains a new access token after force.com rejected
+// expiredToken on a request not sent by ForceApi itself, such as a Pub/Sub
+// API call. When several requests see the same token expire at once, a
+// single new token is obtained.
+func (forceApi *ForceApi) RenewAccessToken(expiredToken string) error {
+	return forceApi.RenewAccessTokenContext(context.Background(), expiredToken)
+}
+
+// RenewAccessTokenContext is like RenewAccessToken but carries ctx to the underlying http request.
+func (forceApi *ForceApi) RenewAccessTokenContext(ctx context.Context, expiredToken string) error {
+	return forceApi.oauth.reauthenticate(ctx, expiredToken)
 }
 
 func (forceApi *ForceApi) RefreshToken() error {
-	res := &RefreshTokenResponse{}
-	payload := map[string]string{
-		"grant_type":    "refresh_token",
-		"refresh_token": forceApi.oauth.refreshToken,
-		"client_id":     forceApi.oauth.clientId,
-		"client_secret": forceApi.oauth.clientSecret,
+	return forceApi.RefreshTokenContext(context.Background())
+}
+
+// RefreshTokenContext is like RefreshToken but carries ctx to the underlying http request.
+func (forceApi *ForceApi) RefreshTokenContext(ctx context.Context) error {
+	oauth := forceApi.oauth
+
+	// Token sources know how to obtain a new token, except for static ones
+	// which are left to the refresh token flow below.
+	if _, static := oauth.source.(*staticTokenSource); oauth.source != nil && !static {
+		return oauth.AuthenticateContext(ctx)
 	}
 
-	err := forceApi.Post("/services/oauth2/token", nil, payload, res)
-	if err != nil {
+	oauth.authMu.Lock()
+	defer oauth.authMu.Unlock()
+
+	if err := oauth.refresh(ctx); err != nil {
 		return err
 	}
 
-	forceApi.oauth.AccessToken = res.AccessToken
+	oauth.tokenRefreshed()
 	return nil
 }
 
 func (forceApi *ForceApi) HasAccess(objectNames []string) bool {
+	sObjects := forceApi.sObjects()
+	if len(sObjects) == 0 {
+		if err := forceApi.getApiSObjects(context.Background()); err != nil {
+			return false
+		}
+		sObjects = forceApi.sObjects()
+	}
+
 	for _, name := range objectNames {
-		if _, ok := forceApi.apiSObjects[name]; !ok {
+		if _, ok := sObjects[name]; !ok {
 			return false
 		}
 	}
@@ -0,0 +1,393 @@
+package force
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/opendoor-labs/go-force/forcecsv"
+)
+
+const (
+	bulkJobsUri      = resourcesUri + "/jobs/%v"
+	bulkIngestJobKey = "ingest"
+	bulkQueryJobKey  = "query"
+
+	csvContentType = "text/csv"
+
+	bulkResultIdColumn      = "sf__Id"
+	bulkResultCreatedColumn = "sf__Created"
+	bulkResultErrorColumn   = "sf__Error"
+
+	// DefaultBulkPollInterval is how often the WaitForBulk* methods check the
+	// state of a job when no interval is given.
+	DefaultBulkPollInterval = 5 * time.Second
+)
+
+// BulkOperation is the kind of work a Bulk API 2.0 job performs.
+type BulkOperation string
+
+const (
+	BulkInsert     BulkOperation = "insert"
+	BulkUpdate     BulkOperation = "update"
+	BulkUpsert     BulkOperation = "upsert"
+	BulkDelete     BulkOperation = "delete"
+	BulkHardDelete BulkOperation = "hardDelete"
+	BulkQuery      BulkOperation = "query"
+	BulkQueryAll   BulkOperation = "queryAll"
+)
+
+// BulkJobState is the processing state of a Bulk API 2.0 job.
+type BulkJobState string
+
+const (
+	BulkJobOpen           BulkJobState = "Open"
+	BulkJobUploadComplete BulkJobState = "UploadComplete"
+	BulkJobInProgress     BulkJobState = "InProgress"
+	BulkJobComplete       BulkJobState = "JobComplete"
+	BulkJobFailed         BulkJobState = "Failed"
+	BulkJobAborted        BulkJobState = "Aborted"
+)
+
+// Done reports whether a job in this state will not change state anymore.
+func (s BulkJobState) Done() bool {
+	return s == BulkJobComplete || s == BulkJobFailed || s == BulkJobAborted
+}
+
+// BulkJob describes a Bulk API 2.0 job as returned by force.com.
+type BulkJob struct {
+	Id                     string        `force:"id,omitempty"`
+	Object                 string        `force:"object,omitempty"`
+	Operation              BulkOperation `force:"operation,omitempty"`
+	State                  BulkJobState  `force:"state,omitempty"`
+	ExternalIdFieldName    string        `force:"externalIdFieldName,omitempty"`
+	ContentType            string        `force:"contentType,omitempty"`
+	LineEnding             string        `force:"lineEnding,omitempty"`
+	ColumnDelimiter        string        `force:"columnDelimiter,omitempty"`
+	ConcurrencyMode        string        `force:"concurrencyMode,omitempty"`
+	ContentUrl             string        `force:"contentUrl,omitempty"`
+	CreatedById            string        `force:"createdById,omitempty"`
+	CreatedDate            string        `force:"createdDate,omitempty"`
+	SystemModstamp         string        `force:"systemModstamp,omitempty"`
+	JobType                string        `force:"jobType,omitempty"`
+	ApiVersion             float64       `force:"apiVersion,omitempty"`
+	NumberRecordsProcessed float64       `force:"numberRecordsProcessed,omitempty"`
+	NumberRecordsFailed    float64       `force:"numberRecordsFailed,omitempty"`
+	Retries                float64       `force:"retries,omitempty"`
+	TotalProcessingTime    float64       `force:"totalProcessingTime,omitempty"`
+	ErrorMessage           string        `force:"errorMessage,omitempty"`
+}
+
+// BulkIngestResult is the outcome of a single record of an ingest job. It is
+// returned alongside the decoded record itself by the Get*Results methods.
+type BulkIngestResult struct {
+	Id      string
+	Created bool
+	Errors  ApiErrors
+}
+
+type bulkIngestJobRequest struct {
+	Object              string        `force:"object"`
+	Operation           BulkOperation `force:"operation"`
+	ExternalIdFieldName string        `force:"externalIdFieldName,omitempty"`
+	ContentType         string        `force:"contentType"`
+	LineEnding          string        `force:"lineEnding"`
+}
+
+type bulkJobStateRequest struct {
+	State BulkJobState `force:"state"`
+}
+
+func (forceApi *ForceApi) bulkJobUri(jobKey, jobId string) string {
+	uri := fmt.Sprintf(bulkJobsUri, forceApi.apiVersion, jobKey)
+	if jobId != "" {
+		uri += "/" + jobId
+	}
+	return uri
+}
+
+func (forceApi *ForceApi) bulkIngestJobUri(jobId string) string {
+	return forceApi.bulkJobUri(bulkIngestJobKey, jobId)
+}
+
+// CreateBulkIngestJob opens a Bulk API 2.0 ingest job that performs operation
+// on the object type of in. externalIdFieldName is required for upserts and
+// ignored otherwise.
+func (forceApi *ForceApi) CreateBulkIngestJob(in SObject, operation BulkOperation, externalIdFieldName string) (*BulkJob, error) {
+	return forceApi.CreateBulkIngestJobContext(context.Background(), in, operation, externalIdFieldName)
+}
+
+// CreateBulkIngestJobContext is like CreateBulkIngestJob but carries ctx to the underlying http request.
+func (forceApi *ForceApi) CreateBulkIngestJobContext(ctx context.Context, in SObject, operation BulkOperation, externalIdFieldName string) (*BulkJob, error) {
+	payload := &bulkIngestJobRequest{
+		Object:      in.APIName(),
+		Operation:   operation,
+		ContentType: "CSV",
+		LineEnding:  "LF",
+	}
+	if operation == BulkUpsert {
+		payload.ExternalIdFieldName = externalIdFieldName
+	}
+
+	job := &BulkJob{}
+	if err := forceApi.PostContext(ctx, forceApi.bulkIngestJobUri(""), nil, payload, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// UploadBulkIngestJobData uploads records, a slice of SObjects, to an open
+// ingest job as CSV. Columns are named after the records' force struct tags.
+// For update and delete jobs the records must carry their Id. See
+// UploadBulkIngestJobCSV for the size limit of a job's data.
+//
+// The CSV is encoded while it is sent rather than built in memory first, so
+// a failed upload isn't sent again; the records can simply be uploaded again.
+func (forceApi *ForceApi) UploadBulkIngestJobData(jobId string, records interface{}) error {
+	return forceApi.UploadBulkIngestJobDataContext(context.Background(), jobId, records)
+}
+
+// UploadBulkIngestJobDataContext is like UploadBulkIngestJobData but carries ctx to the underlying http request.
+func (forceApi *ForceApi) UploadBulkIngestJobDataContext(ctx context.Context, jobId string, records interface{}) error {
+	data, w := io.Pipe()
+	marshalErr := make(chan error, 1)
+	go func() {
+		err := forcecsv.NewEncoder(w).EncodeAll(records)
+		marshalErr <- err
+		w.CloseWithError(err)
+	}()
+
+	err := forceApi.UploadBulkIngestJobCSVContext(ctx, jobId, data)
+	// Stop the encoder in case the request ended before reading everything.
+	data.Close()
+	if mErr := <-marshalErr; mErr != nil && mErr != io.ErrClosedPipe {
+		return fmt.Errorf("Error marshaling bulk job data: %v", mErr)
+	}
+
+	return err
+}
+
+// UploadBulkIngestJobCSV uploads an already encoded CSV document to an open
+// ingest job. data is streamed to force.com rather than read into memory
+// first, and is left open. A failed request is only sent again when data is
+// an io.Seeker, such as an *os.File, from where data stood when handed over.
+//
+// force.com accepts at most 150 MB of data per job once base64 encoded,
+// which amounts to about 100 MB of CSV. Larger data sets have to be split
+// across several jobs.
+func (forceApi *ForceApi) UploadBulkIngestJobCSV(jobId string, data io.Reader) error {
+	return forceApi.UploadBulkIngestJobCSVContext(context.Background(), jobId, data)
+}
+
+// UploadBulkIngestJobCSVContext is like UploadBulkIngestJobCSV but carries ctx to the underlying http request.
+func (forceApi *ForceApi) UploadBulkIngestJobCSVContext(ctx context.Context, jobId string, data io.Reader) error {
+	uri := forceApi.bulkIngestJobUri(jobId) + "/batches"
+	_, _, err := forceApi.requestRaw(ctx, "PUT", uri, nil, csvContentType, responseType, data)
+
+	return err
+}
+
+// CloseBulkIngestJob marks the upload of an ingest job as complete, which
+// queues its data for processing.
+func (forceApi *ForceApi) CloseBulkIngestJob(jobId string) (*BulkJob, error) {
+	return forceApi.CloseBulkIngestJobContext(context.Background(), jobId)
+}
+
+// CloseBulkIngestJobContext is like CloseBulkIngestJob but carries ctx to the underlying http request.
+func (forceApi *ForceApi) CloseBulkIngestJobContext(ctx context.Context, jobId string) (*BulkJob, error) {
+	return forceApi.setBulkJobState(ctx, forceApi.bulkIngestJobUri(jobId), BulkJobUploadComplete)
+}
+
+// AbortBulkIngestJob aborts an ingest job. Records that were already
+// processed are not rolled back.
+func (forceApi *ForceApi) AbortBulkIngestJob(jobId string) (*BulkJob, error) {
+	return forceApi.AbortBulkIngestJobContext(context.Background(), jobId)
+}
+
+// AbortBulkIngestJobContext is like AbortBulkIngestJob but carries ctx to the underlying http request.
+func (forceApi *ForceApi) AbortBulkIngestJobContext(ctx context.Context, jobId string) (*BulkJob, error) {
+	return forceApi.setBulkJobState(ctx, forceApi.bulkIngestJobUri(jobId), BulkJobAborted)
+}
+
+func (forceApi *ForceApi) setBulkJobState(ctx context.Context, uri string, state BulkJobState) (*BulkJob, error) {
+	job := &BulkJob{}
+	if _, err := forceApi.PatchContext(ctx, uri, nil, &bulkJobStateRequest{State: state}, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// DeleteBulkIngestJob deletes a job that is complete, failed or aborted,
+// along with its data.
+func (forceApi *ForceApi) DeleteBulkIngestJob(jobId string) error {
+	return forceApi.DeleteBulkIngestJobContext(context.Background(), jobId)
+}
+
+// DeleteBulkIngestJobContext is like DeleteBulkIngestJob but carries ctx to the underlying http request.
+func (forceApi *ForceApi) DeleteBulkIngestJobContext(ctx context.Context, jobId string) error {
+	return forceApi.DeleteContext(ctx, forceApi.bulkIngestJobUri(jobId), nil)
+}
+
+// GetBulkIngestJob retrieves the current state of an ingest job.
+func (forceApi *ForceApi) GetBulkIngestJob(jobId string) (*BulkJob, error) {
+	return forceApi.GetBulkIngestJobContext(context.Background(), jobId)
+}
+
+// GetBulkIngestJobContext is like GetBulkIngestJob but carries ctx to the underlying http request.
+func (forceApi *ForceApi) GetBulkIngestJobContext(ctx context.Context, jobId string) (*BulkJob, error) {
+	return forceApi.getBulkJob(ctx, forceApi.bulkIngestJobUri(jobId))
+}
+
+func (forceApi *ForceApi) getBulkJob(ctx context.Context, uri string) (*BulkJob, error) {
+	job := &BulkJob{}
+	if _, err := forceApi.GetContext(ctx, uri, nil, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// WaitForBulkIngestJob polls an ingest job every interval until it is
+// complete, failed or aborted, or until ctx is done. A zero interval uses
+// DefaultBulkPollInterval.
+func (forceApi *ForceApi) WaitForBulkIngestJob(ctx context.Context, jobId string, interval time.Duration) (*BulkJob, error) {
+	return forceApi.waitForBulkJob(ctx, forceApi.bulkIngestJobUri(jobId), interval)
+}
+
+func (forceApi *ForceApi) waitForBulkJob(ctx context.Context, uri string, interval time.Duration) (*BulkJob, error) {
+	if interval <= 0 {
+		interval = DefaultBulkPollInterval
+	}
+
+	for {
+		job, err := forceApi.getBulkJob(ctx, uri)
+		if err != nil {
+			return nil, err
+		}
+		if job.State.Done() {
+			return job, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return job, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// GetBulkIngestSuccessfulResults decodes the records an ingest job processed
+// successfully into out, a pointer to a slice of SObjects, and returns the
+// result for each record in the same order.
+func (forceApi *ForceApi) GetBulkIngestSuccessfulResults(jobId string, out interface{}) ([]*BulkIngestResult, error) {
+	return forceApi.GetBulkIngestSuccessfulResultsContext(context.Background(), jobId, out)
+}
+
+// GetBulkIngestSuccessfulResultsContext is like GetBulkIngestSuccessfulResults but carries ctx to the underlying http request.
+func (forceApi *ForceApi) GetBulkIngestSuccessfulResultsContext(ctx context.Context, jobId string, out interface{}) ([]*BulkIngestResult, error) {
+	return forceApi.getBulkIngestResults(ctx, jobId, "successfulResults", out)
+}
+
+// GetBulkIngestFailedResults decodes the records an ingest job failed to
+// process into out, a pointer to a slice of SObjects, and returns the result
+// for each record, including its errors, in the same order.
+func (forceApi *ForceApi) GetBulkIngestFailedResults(jobId string, out interface{}) ([]*BulkIngestResult, error) {
+	return forceApi.GetBulkIngestFailedResultsContext(context.Background(), jobId, out)
+}
+
+// GetBulkIngestFailedResultsContext is like GetBulkIngestFailedResults but carries ctx to the underlying http request.
+func (forceApi *ForceApi) GetBulkIngestFailedResultsContext(ctx context.Context, jobId string, out interface{}) ([]*BulkIngestResult, error) {
+	return forceApi.getBulkIngestResults(ctx, jobId, "failedResults", out)
+}
+
+// GetBulkIngestUnprocessedRecords decodes the records an ingest job never got
+// to, for example because it was aborted, into out, a pointer to a slice of
+// SObjects.
+func (forceApi *ForceApi) GetBulkIngestUnprocessedRecords(jobId string, out interface{}) error {
+	return forceApi.GetBulkIngestUnprocessedRecordsContext(context.Background(), jobId, out)
+}
+
+// GetBulkIngestUnprocessedRecordsContext is like GetBulkIngestUnprocessedRecords but carries ctx to the underlying http request.
+func (forceApi *ForceApi) GetBulkIngestUnprocessedRecordsContext(ctx context.Context, jobId string, out interface{}) error {
+	_, err := forceApi.getBulkIngestResults(ctx, jobId, "unprocessedrecords", out)
+	return err
+}
+
+func (forceApi *ForceApi) getBulkIngestResults(ctx context.Context, jobId, resource string, out interface{}) ([]*BulkIngestResult, error) {
+	slice := reflect.ValueOf(out)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return nil, fmt.Errorf("Unable to decode bulk results into %T, expected a pointer to a slice", out)
+	}
+	slice = slice.Elem()
+	elemType := slice.Type().Elem()
+
+	uri := forceApi.bulkIngestJobUri(jobId) + "/" + resource + "/"
+	_, body, err := forceApi.requestRaw(ctx, "GET", uri, nil, contentType, csvContentType, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*BulkIngestResult
+	dec := forcecsv.NewDecoder(bytes.NewReader(body))
+	for {
+		elem := reflect.New(elemType).Elem()
+		target := elem.Addr()
+		if elemType.Kind() == reflect.Ptr {
+			elem.Set(reflect.New(elemType.Elem()))
+			target = elem
+		}
+
+		err := dec.Decode(target.Interface())
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Unable to decode bulk results: %v", err)
+		}
+		slice.Set(reflect.Append(slice, elem))
+
+		result := &BulkIngestResult{
+			Id:      dec.Value(bulkResultIdColumn),
+			Created: dec.Value(bulkResultCreatedColumn) == "true",
+		}
+		if sfError := dec.Value(bulkResultErrorColumn); sfError != "" {
+			result.Errors = ApiErrors{parseBulkError(sfError)}
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// parseBulkError turns the sf__Error column of a failed record, which looks
+// like "REQUIRED_FIELD_MISSING:Required fields are missing: [Name]:Name --",
+// into an ApiError.
+func parseBulkError(s string) *ApiError {
+	apiError := &ApiError{Message: s}
+
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return apiError
+	}
+	apiError.ErrorCode = parts[0]
+	message := strings.TrimSpace(strings.TrimSuffix(parts[1], "--"))
+
+	// The trailing ":Field1,Field2" lists the offending fields, if any.
+	if idx := strings.LastIndex(message, ":"); idx != -1 {
+		if fields := message[idx+1:]; !strings.ContainsAny(fields, " []") {
+			if fields != "" {
+				apiError.Fields = strings.Split(fields, ",")
+			}
+			message = message[:idx]
+		}
+	}
+	apiError.Message = message
+
+	return apiError
+}
@@ -0,0 +1,236 @@
+package force
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/opendoor-labs/go-force/forcecsv"
+)
+
+const (
+	bulkLocatorHeader = "Sforce-Locator"
+	bulkNoLocator     = "null"
+)
+
+type bulkQueryJobRequest struct {
+	Operation   BulkOperation `force:"operation"`
+	Query       string        `force:"query"`
+	ContentType string        `force:"contentType"`
+	LineEnding  string        `force:"lineEnding"`
+}
+
+func (forceApi *ForceApi) bulkQueryJobUri(jobId string) string {
+	return forceApi.bulkJobUri(bulkQueryJobKey, jobId)
+}
+
+// BulkQuery runs query as a Bulk API 2.0 query job, waits for it to complete
+// and decodes every result row into out, a pointer to a slice of SObjects.
+// Use it instead of Query and QueryNext for large extracts. Set all to
+// include deleted and archived records, like QueryAll.
+func (forceApi *ForceApi) BulkQuery(query string, all bool, out interface{}) error {
+	return forceApi.BulkQueryContext(context.Background(), query, all, out)
+}
+
+// BulkQueryContext is like BulkQuery but carries ctx to the underlying http requests.
+func (forceApi *ForceApi) BulkQueryContext(ctx context.Context, query string, all bool, out interface{}) error {
+	operation := BulkQuery
+	if all {
+		operation = BulkQueryAll
+	}
+
+	job, err := forceApi.CreateBulkQueryJobContext(ctx, query, operation)
+	if err != nil {
+		return err
+	}
+
+	job, err = forceApi.WaitForBulkQueryJob(ctx, job.Id, 0)
+	if err != nil {
+		return err
+	}
+	if job.State != BulkJobComplete {
+		return fmt.Errorf("Bulk query job %v ended in state %v: %v", job.Id, job.State, job.ErrorMessage)
+	}
+
+	return forceApi.GetBulkQueryResultsContext(ctx, job.Id, out)
+}
+
+// CreateBulkQueryJob submits a SOQL query as a Bulk API 2.0 query job.
+// operation must be BulkQuery or BulkQueryAll.
+func (forceApi *ForceApi) CreateBulkQueryJob(query string, operation BulkOperation) (*BulkJob, error) {
+	return forceApi.CreateBulkQueryJobContext(context.Background(), query, operation)
+}
+
+// CreateBulkQueryJobContext is like CreateBulkQueryJob but carries ctx to the underlying http request.
+func (forceApi *ForceApi) CreateBulkQueryJobContext(ctx context.Context, query string, operation BulkOperation) (*BulkJob, error) {
+	payload := &bulkQueryJobRequest{
+		Operation:   operation,
+		Query:       query,
+		ContentType: "CSV",
+		LineEnding:  "LF",
+	}
+
+	job := &BulkJob{}
+	if err := forceApi.PostContext(ctx, forceApi.bulkQueryJobUri(""), nil, payload, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// GetBulkQueryJob retrieves the current state of a query job.
+func (forceApi *ForceApi) GetBulkQueryJob(jobId string) (*BulkJob, error) {
+	return forceApi.GetBulkQueryJobContext(context.Background(), jobId)
+}
+
+// GetBulkQueryJobContext is like GetBulkQueryJob but carries ctx to the underlying http request.
+func (forceApi *ForceApi) GetBulkQueryJobContext(ctx context.Context, jobId string) (*BulkJob, error) {
+	return forceApi.getBulkJob(ctx, forceApi.bulkQueryJobUri(jobId))
+}
+
+// WaitForBulkQueryJob polls a query job every interval until it is complete,
+// failed or aborted, or until ctx is done. A zero interval uses
+// DefaultBulkPollInterval.
+func (forceApi *ForceApi) WaitForBulkQueryJob(ctx context.Context, jobId string, interval time.Duration) (*BulkJob, error) {
+	return forceApi.waitForBulkJob(ctx, forceApi.bulkQueryJobUri(jobId), interval)
+}
+
+// AbortBulkQueryJob aborts a query job that hasn't completed yet.
+func (forceApi *ForceApi) AbortBulkQueryJob(jobId string) (*BulkJob, error) {
+	return forceApi.AbortBulkQueryJobContext(context.Background(), jobId)
+}
+
+// AbortBulkQueryJobContext is like AbortBulkQueryJob but carries ctx to the underlying http request.
+func (forceApi *ForceApi) AbortBulkQueryJobContext(ctx context.Context, jobId string) (*BulkJob, error) {
+	return forceApi.setBulkJobState(ctx, forceApi.bulkQueryJobUri(jobId), BulkJobAborted)
+}
+
+// DeleteBulkQueryJob deletes a query job along with its results.
+func (forceApi *ForceApi) DeleteBulkQueryJob(jobId string) error {
+	return forceApi.DeleteBulkQueryJobContext(context.Background(), jobId)
+}
+
+// DeleteBulkQueryJobContext is like DeleteBulkQueryJob but carries ctx to the underlying http request.
+func (forceApi *ForceApi) DeleteBulkQueryJobContext(ctx context.Context, jobId string) error {
+	return forceApi.DeleteContext(ctx, forceApi.bulkQueryJobUri(jobId), nil)
+}
+
+// GetBulkQueryResultsPage fetches a single page of results of a completed
+// query job and appends its rows to out, a pointer to a slice of SObjects.
+// Pass an empty locator for the first page and the returned locator for the
+// following ones; it is empty once the last page has been read. maxRecords
+// limits the size of the page, zero lets force.com decide.
+func (forceApi *ForceApi) GetBulkQueryResultsPage(jobId, locator string, maxRecords int, out interface{}) (string, error) {
+	return forceApi.GetBulkQueryResultsPageContext(context.Background(), jobId, locator, maxRecords, out)
+}
+
+// GetBulkQueryResultsPageContext is like GetBulkQueryResultsPage but carries ctx to the underlying http request.
+func (forceApi *ForceApi) GetBulkQueryResultsPageContext(ctx context.Context, jobId, locator string, maxRecords int, out interface{}) (string, error) {
+	body, next, err := forceApi.getBulkQueryResultsPage(ctx, jobId, locator, maxRecords)
+	if err != nil {
+		return "", err
+	}
+
+	if err := forcecsv.Unmarshal(body, out); err != nil {
+		return "", fmt.Errorf("Unable to decode bulk query results: %v", err)
+	}
+
+	return next, nil
+}
+
+// GetBulkQueryResults follows every page of results of a completed query job
+// and appends all rows to out, a pointer to a slice of SObjects.
+func (forceApi *ForceApi) GetBulkQueryResults(jobId string, out interface{}) error {
+	return forceApi.GetBulkQueryResultsContext(context.Background(), jobId, out)
+}
+
+// GetBulkQueryResultsContext is like GetBulkQueryResults but carries ctx to the underlying http requests.
+func (forceApi *ForceApi) GetBulkQueryResultsContext(ctx context.Context, jobId string, out interface{}) error {
+	locator := ""
+	for {
+		next, err := forceApi.GetBulkQueryResultsPageContext(ctx, jobId, locator, 0, out)
+		if err != nil {
+			return err
+		}
+		if next == "" {
+			return nil
+		}
+		locator = next
+	}
+}
+
+// EachBulkQueryResult streams the results of a completed query job one row at
+// a time, so they never have to be held in memory all at once. Each row is
+// decoded into record, a pointer to an SObject that is reset beforehand, and
+// fn is called. Returning an error from fn stops the iteration and returns
+// that error.
+func (forceApi *ForceApi) EachBulkQueryResult(jobId string, record interface{}, fn func() error) error {
+	return forceApi.EachBulkQueryResultContext(context.Background(), jobId, record, fn)
+}
+
+// EachBulkQueryResultContext is like EachBulkQueryResult but carries ctx to the underlying http requests.
+func (forceApi *ForceApi) EachBulkQueryResultContext(ctx context.Context, jobId string, record interface{}, fn func() error) error {
+	rv := reflect.ValueOf(record)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("Unable to decode bulk query results into %T, expected a pointer", record)
+	}
+	zero := reflect.Zero(rv.Elem().Type())
+
+	locator := ""
+	for {
+		body, next, err := forceApi.getBulkQueryResultsPage(ctx, jobId, locator, 0)
+		if err != nil {
+			return err
+		}
+
+		dec := forcecsv.NewDecoder(bytes.NewReader(body))
+		for {
+			rv.Elem().Set(zero)
+			err := dec.Decode(record)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("Unable to decode bulk query results: %v", err)
+			}
+			if err := fn(); err != nil {
+				return err
+			}
+		}
+
+		if next == "" {
+			return nil
+		}
+		locator = next
+	}
+}
+
+// getBulkQueryResultsPage returns the raw CSV of a page of results and the
+// locator of the next page, if any.
+func (forceApi *ForceApi) getBulkQueryResultsPage(ctx context.Context, jobId, locator string, maxRecords int) ([]byte, string, error) {
+	params := url.Values{}
+	if locator != "" {
+		params.Set("locator", locator)
+	}
+	if maxRecords > 0 {
+		params.Set("maxRecords", strconv.Itoa(maxRecords))
+	}
+
+	uri := forceApi.bulkQueryJobUri(jobId) + "/results"
+	resp, body, err := forceApi.requestRaw(ctx, "GET", uri, params, contentType, csvContentType, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	next := resp.Header.Get(bulkLocatorHeader)
+	if next == bulkNoLocator {
+		next = ""
+	}
+
+	return body, next, nil
+}
@@ -0,0 +1,100 @@
+package force_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/forcefakes"
+)
+
+func newFakeResultsPage(body, locator string) *http.Response {
+	resp := NewFakeResponse(body, 200)
+	resp.Header = http.Header{"Sforce-Locator": {locator}}
+	return resp
+}
+
+var _ = Describe("Bulk query", func() {
+	var httpClient forcefakes.FakeHttpClient
+	var forceApi *force.ForceApi
+
+	BeforeEach(func() {
+		httpClient = forcefakes.FakeHttpClient{}
+
+		var err error
+		forceApi, err = createForceApi(&httpClient)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("BulkQuery", func() {
+		It("should submit the job, wait for it and read every page", func() {
+			httpClient.DoReturnsOnCall(3, NewFakeResponse(`{"id": "750Q", "operation": "queryAll", "state": "UploadComplete"}`, 200), nil)
+			httpClient.DoReturnsOnCall(4, NewFakeResponse(`{"id": "750Q", "state": "JobComplete"}`, 200), nil)
+			httpClient.DoReturnsOnCall(5, newFakeResultsPage("Id,LastName\n003A,Lovelace\n", "LOC1"), nil)
+			httpClient.DoReturnsOnCall(6, newFakeResultsPage("Id,LastName\n003B,Hopper\n", "null"), nil)
+
+			var contacts []*BulkContact
+			err := forceApi.BulkQuery("SELECT Id, LastName FROM Contact", true, &contacts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contacts).To(HaveLen(2))
+			Expect(contacts[0].Id).To(Equal("003A"))
+			Expect(contacts[1].LastName).To(Equal("Hopper"))
+
+			body, _ := ioutil.ReadAll(httpClient.DoArgsForCall(3).Body)
+			Expect(body).To(MatchJSON(`{"operation": "queryAll", "query": "SELECT Id, LastName FROM Contact", "contentType": "CSV", "lineEnding": "LF"}`))
+
+			Expect(httpClient.DoArgsForCall(5).URL.Path).To(HaveSuffix("/jobs/query/750Q/results"))
+			Expect(httpClient.DoArgsForCall(5).URL.Query().Get("locator")).To(BeEmpty())
+			Expect(httpClient.DoArgsForCall(6).URL.Query().Get("locator")).To(Equal("LOC1"))
+		})
+
+		It("should fail when the job fails", func() {
+			httpClient.DoReturnsOnCall(3, NewFakeResponse(`{"id": "750Q", "state": "UploadComplete"}`, 200), nil)
+			httpClient.DoReturnsOnCall(4, NewFakeResponse(`{"id": "750Q", "state": "Failed", "errorMessage": "bad soql"}`, 200), nil)
+
+			var contacts []*BulkContact
+			err := forceApi.BulkQuery("SELECT Nope FROM Contact", false, &contacts)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("bad soql"))
+		})
+	})
+
+	Describe("GetBulkQueryResultsPage", func() {
+		It("should return the next locator and limit the page size", func() {
+			httpClient.DoReturnsOnCall(3, newFakeResultsPage("Id\n003A\n", "LOC2"), nil)
+
+			var contacts []BulkContact
+			next, err := forceApi.GetBulkQueryResultsPage("750Q", "LOC1", 1, &contacts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next).To(Equal("LOC2"))
+			Expect(contacts).To(HaveLen(1))
+			Expect(httpClient.DoArgsForCall(3).URL.Query().Get("maxRecords")).To(Equal("1"))
+		})
+	})
+
+	Describe("EachBulkQueryResult", func() {
+		It("should call back once per row and stop on error", func() {
+			httpClient.DoReturnsOnCall(3, newFakeResultsPage("Id,Email\n003A,a@b.c\n003B,\n003C,c@d.e\n", "null"), nil)
+
+			contact := BulkContact{}
+			var seen []BulkContact
+			stop := errors.New("stop")
+			err := forceApi.EachBulkQueryResult("750Q", &contact, func() error {
+				seen = append(seen, contact)
+				if len(seen) == 2 {
+					return stop
+				}
+				return nil
+			})
+			Expect(err).To(Equal(stop))
+			Expect(seen).To(HaveLen(2))
+			Expect(seen[0].Email).To(Equal("a@b.c"))
+			Expect(seen[1].Id).To(Equal("003B"))
+			Expect(seen[1].Email).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,234 @@
+package force_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/forcefakes"
+	"github.com/opendoor-labs/go-force/sobjects"
+)
+
+type BulkContact struct {
+	sobjects.BaseSObject
+	LastName string `force:",omitempty"`
+	Email    string `force:",omitempty"`
+}
+
+func (c *BulkContact) APIName() string {
+	return "Contact"
+}
+
+// readBodies makes httpClient read request bodies while sending them, as
+// http.Transport does, and answer with responses in turn. It returns the
+// bodies read so far.
+func readBodies(httpClient *forcefakes.FakeHttpClient, responses ...*http.Response) *[]string {
+	bodies := []string{}
+	httpClient.DoStub = func(req *http.Request) (*http.Response, error) {
+		var body []byte
+		if req.Body != nil {
+			body, _ = ioutil.ReadAll(req.Body)
+		}
+		bodies = append(bodies, string(body))
+		return responses[len(bodies)-1], nil
+	}
+	return &bodies
+}
+
+var _ = Describe("Bulk", func() {
+	var httpClient forcefakes.FakeHttpClient
+	var forceApi *force.ForceApi
+
+	BeforeEach(func() {
+		httpClient = forcefakes.FakeHttpClient{}
+
+		var err error
+		forceApi, err = createForceApi(&httpClient)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("CreateBulkIngestJob", func() {
+		It("should open a CSV job for the object", func() {
+			httpClient.DoReturnsOnCall(3, NewFakeResponse(`{"id": "750A", "object": "Contact", "operation": "upsert", "state": "Open"}`, 200), nil)
+
+			job, err := forceApi.CreateBulkIngestJob(&BulkContact{}, force.BulkUpsert, "Email")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(job.Id).To(Equal("750A"))
+			Expect(job.State).To(Equal(force.BulkJobOpen))
+
+			req := httpClient.DoArgsForCall(3)
+			Expect(req.Method).To(Equal("POST"))
+			Expect(req.URL.Path).To(HaveSuffix("/jobs/ingest"))
+			body, _ := ioutil.ReadAll(req.Body)
+			Expect(body).To(MatchJSON(`{"object": "Contact", "operation": "upsert", "externalIdFieldName": "Email", "contentType": "CSV", "lineEnding": "LF"}`))
+		})
+	})
+
+	Describe("UploadBulkIngestJobData", func() {
+		It("should PUT the records as CSV", func() {
+			bodies := readBodies(&httpClient, NewFakeResponse("", 201))
+
+			records := []*BulkContact{{LastName: "Lovelace", Email: "ada@example.com"}, {LastName: "Hopper"}}
+			err := forceApi.UploadBulkIngestJobData("750A", records)
+			Expect(err).NotTo(HaveOccurred())
+
+			req := httpClient.DoArgsForCall(3)
+			Expect(req.Method).To(Equal("PUT"))
+			Expect(req.URL.Path).To(HaveSuffix("/jobs/ingest/750A/batches"))
+			Expect(req.Header.Get("Content-Type")).To(Equal("text/csv"))
+			Expect(*bodies).To(Equal([]string{"LastName,Email\nLovelace,ada@example.com\nHopper,\n"}))
+		})
+
+		It("should upload records read with a query", func() {
+			bodies := readBodies(&httpClient,
+				NewFakeResponse(`{"totalSize": 1, "done": true, "records": [
+					{"attributes": {"type": "Contact", "url": "/services/data/v52.0/sobjects/Contact/003A"}, "Id": "003A", "LastName": "Lovelace"}
+				]}`, 200),
+				NewFakeResponse("", 201),
+			)
+
+			result := struct {
+				Records []*BulkContact `force:"records"`
+			}{}
+			Expect(forceApi.Query("SELECT Id, LastName FROM Contact", &result)).To(Succeed())
+			Expect(result.Records[0].Attributes.Type).To(Equal("Contact"))
+
+			result.Records[0].Email = "ada@example.com"
+			err := forceApi.UploadBulkIngestJobData("750A", result.Records)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect((*bodies)[1]).To(Equal("Id,LastName,Email\n003A,Lovelace,ada@example.com\n"))
+		})
+
+		It("should stream CSV documents", func() {
+			httpClient.DoReturnsOnCall(3, NewFakeResponse("", 201), nil)
+
+			data, writer := io.Pipe()
+			go func() {
+				writer.Write([]byte("LastName\nLovelace\n"))
+				writer.Close()
+			}()
+
+			err := forceApi.UploadBulkIngestJobCSV("750A", data)
+			Expect(err).NotTo(HaveOccurred())
+
+			req := httpClient.DoArgsForCall(3)
+			Expect(req.ContentLength).To(BeZero())
+			body, _ := ioutil.ReadAll(req.Body)
+			Expect(string(body)).To(Equal("LastName\nLovelace\n"))
+		})
+
+		It("should send a file again from where it stood", func() {
+			file, err := ioutil.TempFile("", "bulk")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.Remove(file.Name())
+			defer file.Close()
+
+			header := "# exported nightly\n"
+			_, err = file.WriteString(header + "LastName\nLovelace\n")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = file.Seek(int64(len(header)), io.SeekStart)
+			Expect(err).NotTo(HaveOccurred())
+
+			var bodies []string
+			var lengths []int64
+			httpClient.DoStub = func(req *http.Request) (*http.Response, error) {
+				// Like http.Transport, read and close the request body.
+				body, _ := ioutil.ReadAll(req.Body)
+				req.Body.Close()
+				bodies = append(bodies, string(body))
+				lengths = append(lengths, req.ContentLength)
+				if len(bodies) == 1 {
+					return NewFakeResponse("Service Unavailable", 503), nil
+				}
+				return NewFakeResponse("", 201), nil
+			}
+			forceApi.SetRetryPolicy(&force.RetryPolicy{InitialBackoff: time.Millisecond})
+
+			Expect(forceApi.UploadBulkIngestJobCSV("750A", file)).To(Succeed())
+			Expect(bodies).To(Equal([]string{"LastName\nLovelace\n", "LastName\nLovelace\n"}))
+			Expect(lengths).To(Equal([]int64{18, 18}))
+
+			// The file is still open for the caller.
+			_, err = file.Seek(0, io.SeekStart)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should report records that can't be marshaled", func() {
+			readBodies(&httpClient, NewFakeResponse("", 201))
+
+			err := forceApi.UploadBulkIngestJobData("750A", []string{"Lovelace"})
+			Expect(err).To(MatchError(ContainSubstring("Error marshaling bulk job data")))
+		})
+
+		It("should return force.com api errors", func() {
+			httpClient.DoReturnsOnCall(3, NewFakeResponse(`[{"errorCode": "INVALIDJOBSTATE", "message": "Job is closed"}]`, 409), nil)
+
+			err := forceApi.UploadBulkIngestJobData("750A", []*BulkContact{{LastName: "Lovelace"}})
+			Expect(err).To(HaveOccurred())
+			var apiErrors force.ApiErrors
+			Expect(errors.As(err, &apiErrors)).To(BeTrue())
+			Expect(apiErrors[0].ErrorCode).To(Equal("INVALIDJOBSTATE"))
+		})
+	})
+
+	Describe("WaitForBulkIngestJob", func() {
+		It("should poll until the job is done", func() {
+			httpClient.DoReturnsOnCall(3, NewFakeResponse(`{"id": "750A", "state": "InProgress"}`, 200), nil)
+			httpClient.DoReturnsOnCall(4, NewFakeResponse(`{"id": "750A", "state": "JobComplete", "numberRecordsProcessed": 2}`, 200), nil)
+
+			job, err := forceApi.WaitForBulkIngestJob(context.Background(), "750A", time.Millisecond)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(job.State).To(Equal(force.BulkJobComplete))
+			Expect(job.NumberRecordsProcessed).To(Equal(float64(2)))
+			Expect(httpClient.DoCallCount()).To(Equal(5))
+		})
+	})
+
+	Describe("GetBulkIngestFailedResults", func() {
+		It("should decode the records with their errors", func() {
+			csv := "\"sf__Id\",\"sf__Error\",LastName,Email\n" +
+				"\"\",\"REQUIRED_FIELD_MISSING:Required fields are missing: [LastName]:LastName --\",,a@b.c\n"
+			httpClient.DoReturnsOnCall(3, NewFakeResponse(csv, 200), nil)
+
+			var records []*BulkContact
+			results, err := forceApi.GetBulkIngestFailedResults("750A", &records)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(HaveLen(1))
+			Expect(records[0].Email).To(Equal("a@b.c"))
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Errors).To(HaveLen(1))
+			Expect(results[0].Errors[0].ErrorCode).To(Equal("REQUIRED_FIELD_MISSING"))
+			Expect(results[0].Errors[0].Message).To(Equal("Required fields are missing: [LastName]"))
+			Expect(results[0].Errors[0].Fields).To(Equal([]string{"LastName"}))
+
+			req := httpClient.DoArgsForCall(3)
+			Expect(req.URL.Path).To(HaveSuffix("/jobs/ingest/750A/failedResults/"))
+			Expect(req.Header.Get("Accept")).To(Equal("text/csv"))
+		})
+	})
+
+	Describe("GetBulkIngestSuccessfulResults", func() {
+		It("should decode the created ids", func() {
+			csv := "sf__Id,sf__Created,LastName\n003A,true,Lovelace\n003B,false,Hopper\n"
+			httpClient.DoReturnsOnCall(3, NewFakeResponse(csv, 200), nil)
+
+			var records []BulkContact
+			results, err := forceApi.GetBulkIngestSuccessfulResults("750A", &records)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records[1].LastName).To(Equal("Hopper"))
+			Expect(results[0].Id).To(Equal("003A"))
+			Expect(results[0].Created).To(BeTrue())
+			Expect(results[1].Created).To(BeFalse())
+			Expect(results[1].Errors).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,171 @@
+package force
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/opendoor-labs/go-force/forcejson"
+)
+
+// Change types of change events. The gap types report changes force.com
+// couldn't capture the fields of, and overflows report transactions too
+// large to be captured; the affected records should be read again.
+const (
+	ChangeTypeCreate         = "CREATE"
+	ChangeTypeUpdate         = "UPDATE"
+	ChangeTypeDelete         = "DELETE"
+	ChangeTypeUndelete       = "UNDELETE"
+	ChangeTypeGapCreate      = "GAP_CREATE"
+	ChangeTypeGapUpdate      = "GAP_UPDATE"
+	ChangeTypeGapDelete      = "GAP_DELETE"
+	ChangeTypeGapUndelete    = "GAP_UNDELETE"
+	ChangeTypeGapOverflow    = "GAP_OVERFLOW"
+	ChangeTypeGapOverflowAll = "GAP_OVERFLOW_ALL"
+)
+
+// ChangeEventsChannel receives the change events of all the objects selected
+// for Change Data Capture. The change events of a single standard object are
+// received on "/data/<Object>ChangeEvent", such as "/data/AccountChangeEvent",
+// and those of a custom object on "/data/<Object>__ChangeEvent".
+const ChangeEventsChannel = "/data/ChangeEvents"
+
+// ChangeEventHeader describes the change a change event reports.
+type ChangeEventHeader struct {
+	EntityName string `json:"entityName"`
+	// RecordIds holds several ids when the same change was made to several
+	// records in one transaction.
+	RecordIds    []string `json:"recordIds"`
+	ChangeType   string   `json:"changeType"`
+	ChangeOrigin string   `json:"changeOrigin"`
+	// TransactionKey identifies the transaction of the change, and
+	// SequenceNumber orders the changes made within it.
+	TransactionKey string `json:"transactionKey"`
+	SequenceNumber int    `json:"sequenceNumber"`
+	// CommitTimestamp is when the transaction was committed, in milliseconds
+	// since the epoch.
+	CommitTimestamp int64  `json:"commitTimestamp"`
+	CommitNumber    int64  `json:"commitNumber"`
+	CommitUser      string `json:"commitUser"`
+	// ChangedFields lists the fields set by an update, NulledFields the ones
+	// set to null, and DiffFields the ones sent as a diff.
+	ChangedFields []string `json:"changedFields"`
+	NulledFields  []string `json:"nulledFields"`
+	DiffFields    []string `json:"diffFields"`
+}
+
+// CommitTime returns CommitTimestamp as a time.
+func (h *ChangeEventHeader) CommitTime() time.Time {
+	return time.Unix(0, h.CommitTimestamp*int64(time.Millisecond))
+}
+
+// ChangeEvent is a change to records captured by Change Data Capture.
+type ChangeEvent struct {
+	Channel  string
+	ReplayId int64
+	Header   ChangeEventHeader
+	// Fields holds the raw fields of the event: all the fields set on the
+	// record for a creation, and only the changed ones for an update.
+	Fields json.RawMessage
+}
+
+type changeEventPayload struct {
+	ChangeEventHeader *ChangeEventHeader `json:"ChangeEventHeader"`
+}
+
+// ChangeEvent decodes the message as a change event.
+func (m *StreamingMessage) ChangeEvent() (*ChangeEvent, error) {
+	data := &streamingData{}
+	if err := json.Unmarshal(m.Data, data); err != nil {
+		return nil, fmt.Errorf("Unable to unmarshal change event: %v", err)
+	}
+
+	payload := &changeEventPayload{}
+	if err := json.Unmarshal(data.Payload, payload); err != nil {
+		return nil, fmt.Errorf("Unable to unmarshal change event: %v", err)
+	}
+	if payload.ChangeEventHeader == nil {
+		return nil, fmt.Errorf("Streaming message on %v is not a change event", m.Channel)
+	}
+
+	return &ChangeEvent{
+		Channel:  m.Channel,
+		ReplayId: m.ReplayId,
+		Header:   *payload.ChangeEventHeader,
+		Fields:   data.Payload,
+	}, nil
+}
+
+// Decode unmarshals the fields of the event into out, with the same rules as
+// the rest of the api, so that the SObject structs used with InsertSObject
+// and friends can be used here too. Fields the event doesn't hold, such as
+// the fields an update didn't change, are left untouched, and so is the Id
+// of out, which is found in Header.RecordIds instead. Compound fields, such
+// as the Name of a Contact, are nested objects.
+func (e *ChangeEvent) Decode(out interface{}) error {
+	if err := forcejson.Unmarshal(e.Fields, out); err != nil {
+		return fmt.Errorf("Unable to unmarshal change event: %v", err)
+	}
+
+	return nil
+}
+
+// SubscribeChangeEvents receives the change events of the given channels and
+// calls handler with each of them, until ctx is done, handler returns an
+// error, or the streaming client fails for good, and returns why it stopped.
+//
+// When store is not nil, each channel resumes after the replay id stored for
+// it, and the replay id of every event is saved once handler processed it
+// successfully, so that no event is lost across restarts. Channels with no
+// stored replay id, and all of them when store is nil, only receive the
+// events published after subscribing.
+func (forceApi *ForceApi) SubscribeChangeEvents(ctx context.Context, store ReplayStore, handler func(*ChangeEvent) error, channels ...string) error {
+	subscriptions := make([]StreamingSubscription, len(channels))
+	for i, channel := range channels {
+		subscriptions[i] = StreamingSubscription{Channel: channel, ReplayId: ReplayNew}
+		if store == nil {
+			continue
+		}
+
+		replayId, ok, err := store.Load(ctx, channel)
+		if err != nil {
+			return err
+		}
+		if ok {
+			subscriptions[i].ReplayId = replayId
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	client := forceApi.NewStreamingClient(subscriptions...)
+	messages, err := client.Start(ctx)
+	if err != nil {
+		return err
+	}
+
+	for message := range messages {
+		event, err := message.ChangeEvent()
+		if err != nil {
+			return err
+		}
+
+		if err := handler(event); err != nil {
+			return err
+		}
+
+		if store != nil {
+			if err := store.Save(ctx, message.Channel, message.ReplayId); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := client.Err(); err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
@@ -0,0 +1,150 @@
+package force_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/forcefakes"
+)
+
+const accountChangeEvent = `{
+	"channel": "/data/AccountChangeEvent",
+	"data": {
+		"schema": "IeRuaY6cbI_HsV8Rv1Mc5g",
+		"event": {"replayId": %v},
+		"payload": {
+			"ChangeEventHeader": {
+				"entityName": "Account",
+				"recordIds": ["001A"],
+				"changeType": "UPDATE",
+				"changeOrigin": "com/salesforce/api/soap/58.0;client=Astro",
+				"transactionKey": "0002343d-9d90-e395-ed20-cf416ba652ad",
+				"sequenceNumber": 1,
+				"commitTimestamp": 1700000000000,
+				"commitNumber": 10960535226,
+				"commitUser": "005A",
+				"changedFields": ["Name", "LastModifiedDate"],
+				"nulledFields": [],
+				"diffFields": []
+			},
+			"Name": "%v",
+			"LastModifiedDate": "2023-11-14T22:13:20.000Z"
+		}
+	}
+}`
+
+var _ = Describe("ChangeEvents", func() {
+	var (
+		server   *fakeCometd
+		forceApi *force.ForceApi
+	)
+
+	BeforeEach(func() {
+		server = &fakeCometd{}
+		httpClient := &forcefakes.FakeHttpClient{DoStub: server.Do}
+
+		var err error
+		forceApi, err = force.New(
+			force.WithHttpClient(httpClient),
+			force.WithPassword("id", "secret", "user", "pass", "token"),
+			force.WithLazyMetadata(),
+		)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should decode change events", func() {
+		server.connects = []func() *http.Response{
+			connectReply(fmt.Sprintf(accountChangeEvent, 3, "Acme")),
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		messages, err := forceApi.NewStreamingClient(force.StreamingSubscription{Channel: "/data/AccountChangeEvent", ReplayId: force.ReplayNew}).Start(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		event, err := (<-messages).ChangeEvent()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(event.Channel).To(Equal("/data/AccountChangeEvent"))
+		Expect(event.ReplayId).To(Equal(int64(3)))
+		Expect(event.Header).To(Equal(force.ChangeEventHeader{
+			EntityName:      "Account",
+			RecordIds:       []string{"001A"},
+			ChangeType:      force.ChangeTypeUpdate,
+			ChangeOrigin:    "com/salesforce/api/soap/58.0;client=Astro",
+			TransactionKey:  "0002343d-9d90-e395-ed20-cf416ba652ad",
+			SequenceNumber:  1,
+			CommitTimestamp: 1700000000000,
+			CommitNumber:    10960535226,
+			CommitUser:      "005A",
+			ChangedFields:   []string{"Name", "LastModifiedDate"},
+			NulledFields:    []string{},
+			DiffFields:      []string{},
+		}))
+		Expect(event.Header.CommitTime().Equal(time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC))).To(BeTrue())
+
+		account := &CompositeSObject{AccountId: "unchanged"}
+		Expect(event.Decode(account)).To(Succeed())
+		Expect(account).To(Equal(&CompositeSObject{Name: "Acme", AccountId: "unchanged"}))
+	})
+
+	It("should resume from and checkpoint replay ids", func() {
+		server.connects = []func() *http.Response{
+			connectReply(fmt.Sprintf(accountChangeEvent, 3, "Acme"), fmt.Sprintf(accountChangeEvent, 4, "Initech")),
+		}
+
+		store := force.NewMemoryReplayStore()
+		Expect(store.Save(context.Background(), "/data/AccountChangeEvent", 2)).To(Succeed())
+
+		stop := errors.New("stop")
+		var names []string
+		err := forceApi.SubscribeChangeEvents(context.Background(), store, func(event *force.ChangeEvent) error {
+			account := &CompositeSObject{}
+			if err := event.Decode(account); err != nil {
+				return err
+			}
+			names = append(names, account.Name)
+			if len(names) == 2 {
+				return stop
+			}
+			return nil
+		}, "/data/AccountChangeEvent")
+		Expect(err).To(Equal(stop))
+		Expect(names).To(Equal([]string{"Acme", "Initech"}))
+
+		Expect(server.request(1)["ext"]).To(Equal(map[string]interface{}{
+			"replay": map[string]interface{}{"/data/AccountChangeEvent": float64(2)},
+		}))
+
+		// The event the handler failed on is received again next time.
+		replayId, ok, err := store.Load(context.Background(), "/data/AccountChangeEvent")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(replayId).To(Equal(int64(3)))
+	})
+
+	It("should subscribe to new events without a stored replay id", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			defer GinkgoRecover()
+			Eventually(server.channels).Should(ContainElement("/meta/connect"))
+			cancel()
+		}()
+
+		err := forceApi.SubscribeChangeEvents(ctx, force.NewMemoryReplayStore(), func(event *force.ChangeEvent) error {
+			return nil
+		}, force.ChangeEventsChannel)
+		Expect(err).To(Equal(context.Canceled))
+
+		Expect(server.request(1)["ext"]).To(Equal(map[string]interface{}{
+			"replay": map[string]interface{}{"/data/ChangeEvents": float64(-1)},
+		}))
+	})
+})
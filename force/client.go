@@ -2,6 +2,7 @@ package force
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -22,49 +23,68 @@ const (
 // Get issues a GET to the specified path with the given params and put the
 // umarshalled (json) result in the third parameter
 func (forceApi *ForceApi) Get(path string, params url.Values, out interface{}) (int, error) {
-	return forceApi.request("GET", path, params, nil, out)
+	return forceApi.GetContext(context.Background(), path, params, out)
+}
+
+// GetContext is like Get but carries ctx to the underlying http request.
+func (forceApi *ForceApi) GetContext(ctx context.Context, path string, params url.Values, out interface{}) (int, error) {
+	return forceApi.request(ctx, "GET", path, params, nil, out)
 }
 
 // Post issues a POST to the specified path with the given params and payload
 // and put the unmarshalled (json) result in the third parameter
 func (forceApi *ForceApi) Post(path string, params url.Values, payload, out interface{}) error {
-	_, err := forceApi.request("POST", path, params, payload, out)
+	return forceApi.PostContext(context.Background(), path, params, payload, out)
+}
+
+// PostContext is like Post but carries ctx to the underlying http request.
+func (forceApi *ForceApi) PostContext(ctx context.Context, path string, params url.Values, payload, out interface{}) error {
+	_, err := forceApi.request(ctx, "POST", path, params, payload, out)
 	return err
 }
 
 // Put issues a PUT to the specified path with the given params and payload
 // and put the unmarshalled (json) result in the third parameter
 func (forceApi *ForceApi) Put(path string, params url.Values, payload, out interface{}) error {
-	_, err := forceApi.request("PUT", path, params, payload, out)
+	return forceApi.PutContext(context.Background(), path, params, payload, out)
+}
+
+// PutContext is like Put but carries ctx to the underlying http request.
+func (forceApi *ForceApi) PutContext(ctx context.Context, path string, params url.Values, payload, out interface{}) error {
+	_, err := forceApi.request(ctx, "PUT", path, params, payload, out)
 	return err
 }
 
 // Patch issues a PATCH to the specified path with the given params and payload
 // and put the unmarshalled (json) result in the third parameter
 func (forceApi *ForceApi) Patch(path string, params url.Values, payload, out interface{}) (int, error) {
-	return forceApi.request("PATCH", path, params, payload, out)
+	return forceApi.PatchContext(context.Background(), path, params, payload, out)
+}
+
+// PatchContext is like Patch but carries ctx to the underlying http request.
+func (forceApi *ForceApi) PatchContext(ctx context.Context, path string, params url.Values, payload, out interface{}) (int, error) {
+	return forceApi.request(ctx, "PATCH", path, params, payload, out)
 }
 
 // Delete issues a DELETE to the specified path with the given payload
 func (forceApi *ForceApi) Delete(path string, params url.Values) error {
-	_, err := forceApi.request("DELETE", path, params, nil, nil)
-	return err
+	return forceApi.DeleteContext(context.Background(), path, params)
 }
 
-func (forceApi *ForceApi) request(method, path string, params url.Values, payload, out interface{}) (int, error) {
-	if err := forceApi.oauth.Validate(); err != nil {
-		return 0, fmt.Errorf("Error creating %v request: %v", method, err)
-	}
+// DeleteContext is like Delete but carries ctx to the underlying http request.
+func (forceApi *ForceApi) DeleteContext(ctx context.Context, path string, params url.Values) error {
+	_, err := forceApi.request(ctx, "DELETE", path, params, nil, nil)
+	return err
+}
 
-	// Build Uri
-	var uri bytes.Buffer
-	uri.WriteString(forceApi.oauth.InstanceUrl)
-	uri.WriteString(path)
-	if params != nil && len(params) != 0 {
-		uri.WriteString("?")
-		uri.WriteString(params.Encode())
-	}
+func (forceApi *ForceApi) request(ctx context.Context, method, path string, params url.Values, payload, out interface{}) (int, error) {
+	return forceApi.doRequest(ctx, method, path, params, payload, out, false)
+}
 
+// doRequest sends the request of request. When force.com rejects the session
+// it reauthenticates and sends the request again, unless it already did, so
+// that a token source handing back rejected tokens can't loop forever.
+func (forceApi *ForceApi) doRequest(ctx context.Context, method, path string, params url.Values, payload, out interface{}, reauthenticated bool) (int, error) {
 	// Build body
 	var body io.Reader
 	if payload != nil {
@@ -77,38 +97,20 @@ func (forceApi *ForceApi) request(method, path string, params url.Values, payloa
 		body = bytes.NewReader(jsonBytes)
 	}
 
-	// Build Request
-	req, err := http.NewRequest(method, uri.String(), body)
-	if err != nil {
-		return 0, fmt.Errorf("Error creating %v request: %v", method, err)
-	}
-
-	// Add Headers
-	req.Header.Set("User-Agent", userAgent)
-	req.Header.Set("Content-Type", contentType)
-	req.Header.Set("Accept", responseType)
-	req.Header.Set("Authorization", fmt.Sprintf("%v %v", "Bearer", forceApi.oauth.AccessToken))
-
-	// Send
-	forceApi.traceRequest(req)
-	resp, err := forceApi.httpClient.Do(req)
+	accessToken := forceApi.oauth.token().AccessToken
+	resp, respBytes, err := forceApi.send(ctx, method, path, params, contentType, responseType, body)
 	if err != nil {
-		return 0, fmt.Errorf("Error sending %v request: %v", method, err)
+		if resp != nil {
+			return resp.StatusCode, err
+		}
+		return 0, err
 	}
-	defer resp.Body.Close()
-	forceApi.traceResponse(resp)
 	statusCode := resp.StatusCode
 	// Sometimes the force API returns no body, we should catch this early
-	if resp.StatusCode == http.StatusNoContent {
+	if statusCode == http.StatusNoContent {
 		return statusCode, nil
 	}
 
-	respBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return statusCode, fmt.Errorf("Error reading response bytes: %v", err)
-	}
-	forceApi.traceResponseBody(respBytes)
-
 	// Attempt to parse response into out
 	var objectUnmarshalErr error
 	if out != nil {
@@ -123,20 +125,24 @@ func (forceApi *ForceApi) request(method, path string, params url.Values, payloa
 	if marshalErr := forcejson.Unmarshal(respBytes, &apiErrors); marshalErr == nil {
 		if apiErrors.Validate() {
 			// Check if error is oauth token expired
-			if forceApi.oauth.Expired(apiErrors) {
+			if forceApi.oauth.Expired(apiErrors) && !reauthenticated {
 				// Reauthenticate then attempt query again
-				oauthErr := forceApi.oauth.Authenticate()
+				oauthErr := forceApi.oauth.reauthenticate(ctx, accessToken)
 				if oauthErr != nil {
 					return statusCode, oauthErr
 				}
 
-				return forceApi.request(method, path, params, payload, out)
+				return forceApi.doRequest(ctx, method, path, params, payload, out, true)
 			}
 
-			return statusCode, apiErrors
+			return statusCode, forceApi.requestError(statusCode, method, path, respBytes, apiErrors)
 		}
 	}
 
+	if statusCode >= http.StatusBadRequest {
+		return statusCode, forceApi.requestError(statusCode, method, path, respBytes, nil)
+	}
+
 	if objectUnmarshalErr != nil {
 		// Not a force.com api error. Just an unmarshalling error.
 		return statusCode, fmt.Errorf("Unable to unmarshal response to object: %v", objectUnmarshalErr)
@@ -146,20 +152,236 @@ func (forceApi *ForceApi) request(method, path string, params url.Values, payloa
 	return statusCode, nil
 }
 
-func (forceApi *ForceApi) traceRequest(req *http.Request) {
-	if forceApi.logger != nil {
-		forceApi.trace("Request:", req, "%v")
+// requestRaw is like request but sends body as is with the given content type
+// and hands back the raw response instead of unmarshalling it. It is used by
+// resources that don't speak json, such as the CSV based Bulk API. Responses
+// with a status code of 400 or above are parsed as force.com api errors.
+// body is streamed and left open; when it isn't an io.Seeker it is sent only
+// once, and a request rejected because the session expired isn't sent again.
+func (forceApi *ForceApi) requestRaw(ctx context.Context, method, path string, params url.Values, contentType, accept string, body io.Reader) (*http.Response, []byte, error) {
+	return forceApi.doRequestRaw(ctx, method, path, params, contentType, accept, newRequestBody(body), false)
+}
+
+// doRequestRaw is to requestRaw what doRequest is to request.
+func (forceApi *ForceApi) doRequestRaw(ctx context.Context, method, path string, params url.Values, contentType, accept string, body *requestBody, reauthenticated bool) (*http.Response, []byte, error) {
+	accessToken := forceApi.oauth.token().AccessToken
+	resp, respBytes, err := forceApi.send(ctx, method, path, params, contentType, accept, body)
+	if err != nil {
+		return nil, nil, err
 	}
+
+	if resp.StatusCode < http.StatusBadRequest {
+		return resp, respBytes, nil
+	}
+
+	var apiErrors ApiErrors
+	if marshalErr := forcejson.Unmarshal(respBytes, &apiErrors); marshalErr != nil || !apiErrors.Validate() {
+		apiErrors = nil
+	}
+
+	// Check if error is oauth token expired
+	if forceApi.oauth.Expired(apiErrors) && !reauthenticated && body.rewind() {
+		// Reauthenticate then attempt request again
+		oauthErr := forceApi.oauth.reauthenticate(ctx, accessToken)
+		if oauthErr != nil {
+			return resp, nil, oauthErr
+		}
+
+		return forceApi.doRequestRaw(ctx, method, path, params, contentType, accept, body, true)
+	}
+
+	return resp, respBytes, forceApi.requestError(resp.StatusCode, method, path, respBytes, apiErrors)
 }
 
-func (forceApi *ForceApi) traceResponse(resp *http.Response) {
-	if forceApi.logger != nil {
-		forceApi.trace("Response:", resp, "%v")
+// requestError builds the error of a request force.com answered with an
+// error. Responses carrying force.com api errors return the ApiErrors
+// themselves unless the ForceApi was built WithRequestErrors.
+func (forceApi *ForceApi) requestError(statusCode int, method, path string, respBytes []byte, apiErrors ApiErrors) error {
+	if len(apiErrors) != 0 && !forceApi.requestErrors {
+		return apiErrors
+	}
+
+	return &RequestError{
+		StatusCode: statusCode,
+		Method:     method,
+		Path:       path,
+		Body:       respBytes,
+		Errors:     apiErrors,
 	}
 }
 
-func (forceApi *ForceApi) traceResponseBody(body []byte) {
-	if forceApi.logger != nil {
-		forceApi.trace("Response Body:", string(body), "%s")
+// send issues an http request against the instance url and returns the
+// response along with its fully read body. The response body is closed.
+// Failed attempts are retried according to the retry policy, if any, as long
+// as body can be rewound.
+func (forceApi *ForceApi) send(ctx context.Context, method, path string, params url.Values, contentType, accept string, body io.Reader) (*http.Response, []byte, error) {
+	if err := forceApi.oauth.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("Error creating %v request: %v", method, err)
 	}
+
+	// Build Uri
+	var uri bytes.Buffer
+	uri.WriteString(forceApi.oauth.token().InstanceUrl)
+	uri.WriteString(path)
+	if params != nil && len(params) != 0 {
+		uri.WriteString("?")
+		uri.WriteString(params.Encode())
+	}
+
+	forceApi.mu.RLock()
+	retryPolicy := forceApi.retryPolicy
+	forceApi.mu.RUnlock()
+
+	reqBody := newRequestBody(body)
+	for attempt := 1; ; attempt++ {
+		resp, respBytes, err := forceApi.sendOnce(ctx, method, uri.String(), contentType, accept, reqBody)
+		if !retryPolicy.shouldRetry(ctx, method, attempt, resp, respBytes, err) || !reqBody.rewind() {
+			return resp, respBytes, err
+		}
+
+		wait := retryPolicy.backoff(attempt)
+		forceApi.trace("Retrying after:", wait, "%v")
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, nil, fmt.Errorf("Error sending %v request: %v", method, err)
+		}
+	}
+}
+
+// sendOnce makes a single attempt at sending a request to uri.
+func (forceApi *ForceApi) sendOnce(ctx context.Context, method, uri, contentType, accept string, body *requestBody) (*http.Response, []byte, error) {
+	// Build Request
+	var reqBody io.Reader
+	if body != nil {
+		// Keep the transport from closing the caller's reader.
+		reqBody = ioutil.NopCloser(body)
+	}
+	req, err := http.NewRequest(method, uri, reqBody)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error creating %v request: %v", method, err)
+	}
+	req = req.WithContext(ctx)
+	if body != nil && body.size >= 0 {
+		req.ContentLength = body.size
+		req.GetBody = body.getBody
+		if body.size == 0 {
+			req.Body = http.NoBody
+		}
+	}
+
+	// Add Headers
+	for key, values := range forceApi.defaultHeader {
+		req.Header[key] = values
+	}
+	for key, values := range contextHeader(ctx) {
+		req.Header[key] = values
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", accept)
+	req.Header.Set("Authorization", fmt.Sprintf("%v %v", "Bearer", forceApi.oauth.token().AccessToken))
+
+	// Send
+	forceApi.traceRequest(req)
+	resp, err := forceApi.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error sending %v request: %v", method, err)
+	}
+	defer resp.Body.Close()
+	forceApi.traceResponse(resp)
+	// Sometimes the force API returns no body, we should catch this early
+	if resp.StatusCode == http.StatusNoContent {
+		return resp, nil, nil
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return resp, nil, fmt.Errorf("Error reading response bytes: %v", err)
+	}
+	forceApi.traceResponseBody(respBytes)
+
+	return resp, respBytes, nil
+}
+
+// requestBody is the body of a request. When it is an io.Seeker it
+// remembers where it started, and how long it is, so that it can be sent
+// again from there.
+type requestBody struct {
+	r      io.Reader
+	seeker io.Seeker
+	start  int64
+	// size is the number of bytes left to send, or -1 when unknown.
+	size int64
+}
+
+// newRequestBody returns the body of a request sending r from where it
+// stands now, or nil when r is nil.
+func newRequestBody(r io.Reader) *requestBody {
+	if r == nil {
+		return nil
+	}
+	if body, ok := r.(*requestBody); ok {
+		return body
+	}
+
+	body := &requestBody{r: r, size: -1}
+	seeker, ok := r.(io.Seeker)
+	if !ok {
+		return body
+	}
+
+	start, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return body
+	}
+	end, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return body
+	}
+	if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+		return body
+	}
+
+	body.seeker = seeker
+	body.start = start
+	body.size = end - start
+	return body
+}
+
+func (body *requestBody) Read(p []byte) (int, error) {
+	return body.r.Read(p)
+}
+
+// rewind moves body back to where it started so that it can be sent again,
+// and reports whether it could.
+func (body *requestBody) rewind() bool {
+	if body == nil {
+		return true
+	}
+	if body.seeker == nil {
+		return false
+	}
+
+	_, err := body.seeker.Seek(body.start, io.SeekStart)
+	return err == nil
+}
+
+// getBody is used as http.Request.GetBody, for redirects.
+func (body *requestBody) getBody() (io.ReadCloser, error) {
+	if !body.rewind() {
+		return nil, fmt.Errorf("Unable to rewind request body")
+	}
+
+	return ioutil.NopCloser(body), nil
+}
+
+func (forceApi *ForceApi) traceRequest(req *http.Request) {
+	forceApi.trace("Request:", req, "%v")
+}
+
+func (forceApi *ForceApi) traceResponse(resp *http.Response) {
+	forceApi.trace("Response:", resp, "%v")
+}
+
+func (forceApi *ForceApi) traceResponseBody(body []byte) {
+	forceApi.trace("Response Body:", body, "%s")
 }
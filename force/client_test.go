@@ -1,6 +1,8 @@
 package force_test
 
 import (
+	"context"
+	"net/http"
 	"net/url"
 
 	. "github.com/onsi/ginkgo"
@@ -10,7 +12,7 @@ import (
 	"github.com/opendoor-labs/go-force/sobjects"
 )
 
-const oauthRespBody = `{"access_token": "at", "instance_url": "iu", "id": "anid", "issued_at": "ia", "signature": "sig"}`
+const oauthRespBody = `{"access_token": "at", "instance_url": "https://iu.my.salesforce.com", "id": "anid", "issued_at": "ia", "signature": "sig"}`
 
 var _ = Describe("Client", func() {
 	Describe("Get", func() {
@@ -32,4 +34,42 @@ var _ = Describe("Client", func() {
 			Expect(sobj.Id).To(Equal("SFID-123"))
 		})
 	})
+
+	Describe("GetContext", func() {
+		It("should send the request with the given context", func() {
+			httpClient := forcefakes.FakeHttpClient{}
+
+			forceApi, err := createForceApi(&httpClient)
+			Expect(err).NotTo(HaveOccurred())
+
+			apiSObjectsResp := NewFakeResponse(`{"Id": "SFID-123"}`, 200)
+			httpClient.DoReturnsOnCall(3, apiSObjectsResp, nil)
+
+			type ctxKey struct{}
+			ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+			sobj := sobjects.BaseSObject{}
+			_, err = forceApi.GetContext(ctx, "/sobjects/path", nil, &sobj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(httpClient.DoArgsForCall(3).Context().Value(ctxKey{})).To(Equal("value"))
+		})
+
+		It("should return the error of a cancelled context", func() {
+			httpClient := forcefakes.FakeHttpClient{}
+
+			forceApi, err := createForceApi(&httpClient)
+			Expect(err).NotTo(HaveOccurred())
+
+			httpClient.DoStub = func(req *http.Request) (*http.Response, error) {
+				return nil, req.Context().Err()
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			err = forceApi.QueryContext(ctx, "SELECT Id FROM Account", &sobjects.BaseQuery{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(context.Canceled.Error()))
+		})
+	})
 })
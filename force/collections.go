@@ -0,0 +1,280 @@
+package force
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/opendoor-labs/go-force/forcejson"
+)
+
+const (
+	collectionsUri = compositeUri + "/sobjects"
+
+	allOrNoneRolledBack = "ALL_OR_NONE_OPERATION_ROLLED_BACK"
+
+	// MaxCollectionSize is the most records force.com accepts in a single
+	// sObject Collections request. Larger slices are sent in several requests.
+	MaxCollectionSize = 200
+)
+
+type collectionRequestBody struct {
+	AllOrNone bool                    `force:"allOrNone"`
+	Records   []*forcejson.RawMessage `force:"records"`
+}
+
+// InsertSObjects creates up to 200 records per request using the sObject
+// Collections resource, sending several requests for larger slices. The
+// records may be of different types. The returned responses are aligned with
+// in; check their Success and Errors for the outcome of each record.
+//
+// allOrNone rolls back every record of a request when one of them fails.
+// Since force.com only applies it per request, records sent in earlier
+// requests stay committed; no further requests are sent once one has failed,
+// so fewer responses than records are returned along with an error.
+func (forceApi *ForceApi) InsertSObjects(allOrNone bool, in []SObject) ([]*SObjectResponse, error) {
+	return forceApi.InsertSObjectsContext(context.Background(), allOrNone, in)
+}
+
+// InsertSObjectsContext is like InsertSObjects but carries ctx to the underlying http requests.
+func (forceApi *ForceApi) InsertSObjectsContext(ctx context.Context, allOrNone bool, in []SObject) ([]*SObjectResponse, error) {
+	uri := fmt.Sprintf(collectionsUri, forceApi.apiVersion)
+	return forceApi.writeSObjects(ctx, "POST", uri, allOrNone, in)
+}
+
+// UpdateSObjects updates up to 200 records per request using the sObject
+// Collections resource. Every record must have its Id set. See InsertSObjects
+// for how responses and allOrNone work.
+func (forceApi *ForceApi) UpdateSObjects(allOrNone bool, in []SObject) ([]*SObjectResponse, error) {
+	return forceApi.UpdateSObjectsContext(context.Background(), allOrNone, in)
+}
+
+// UpdateSObjectsContext is like UpdateSObjects but carries ctx to the underlying http requests.
+func (forceApi *ForceApi) UpdateSObjectsContext(ctx context.Context, allOrNone bool, in []SObject) ([]*SObjectResponse, error) {
+	uri := fmt.Sprintf(collectionsUri, forceApi.apiVersion)
+	return forceApi.writeSObjects(ctx, "PATCH", uri, allOrNone, in)
+}
+
+// UpsertSObjectsByExternalId upserts up to 200 records per request keyed on
+// the externalKey field, which every record must have set. All records must be
+// of the same type. Created is set on the responses of new records. See
+// InsertSObjects for how responses and allOrNone work.
+func (forceApi *ForceApi) UpsertSObjectsByExternalId(allOrNone bool, externalKey string, in []SObject) ([]*SObjectResponse, error) {
+	return forceApi.UpsertSObjectsByExternalIdContext(context.Background(), allOrNone, externalKey, in)
+}
+
+// UpsertSObjectsByExternalIdContext is like UpsertSObjectsByExternalId but carries ctx to the underlying http requests.
+func (forceApi *ForceApi) UpsertSObjectsByExternalIdContext(ctx context.Context, allOrNone bool, externalKey string, in []SObject) ([]*SObjectResponse, error) {
+	if len(in) == 0 {
+		return []*SObjectResponse{}, nil
+	}
+
+	apiName := in[0].APIName()
+	for _, record := range in {
+		if record.APIName() != apiName {
+			return nil, fmt.Errorf("Unable to upsert records of different types: %v and %v", apiName, record.APIName())
+		}
+	}
+
+	uri := fmt.Sprintf(collectionsUri+"/%v/%v", forceApi.apiVersion, apiName, externalKey)
+	return forceApi.writeSObjects(ctx, "PATCH", uri, allOrNone, in)
+}
+
+// DeleteSObjects deletes up to 200 records per request by id. See
+// InsertSObjects for how responses and allOrNone work.
+func (forceApi *ForceApi) DeleteSObjects(allOrNone bool, ids []string) ([]*SObjectResponse, error) {
+	return forceApi.DeleteSObjectsContext(context.Background(), allOrNone, ids)
+}
+
+// DeleteSObjectsContext is like DeleteSObjects but carries ctx to the underlying http requests.
+func (forceApi *ForceApi) DeleteSObjectsContext(ctx context.Context, allOrNone bool, ids []string) ([]*SObjectResponse, error) {
+	uri := fmt.Sprintf(collectionsUri, forceApi.apiVersion)
+
+	resps := make([]*SObjectResponse, 0, len(ids))
+	for start := 0; start < len(ids); start += MaxCollectionSize {
+		end := collectionChunkEnd(start, len(ids))
+
+		params := url.Values{
+			"ids":       {strings.Join(ids[start:end], ",")},
+			"allOrNone": {fmt.Sprint(allOrNone)},
+		}
+
+		chunk := []*SObjectResponse{}
+		if err := forceApi.collectionRequest(ctx, "DELETE", uri, params, nil, &chunk); err != nil {
+			return resps, err
+		}
+
+		resps = append(resps, chunk...)
+		if err := checkCollectionChunk(allOrNone, start, end, chunk); err != nil {
+			return resps, err
+		}
+	}
+
+	return resps, nil
+}
+
+// GetSObjects retrieves records of a single type by id, up to 200 per
+// request, and appends them to out, a pointer to a slice of SObjects. The
+// records are aligned with ids; ids that don't match a record leave a zero
+// value, or nil for a slice of pointers. fields must not be empty.
+func (forceApi *ForceApi) GetSObjects(ids []string, fields []string, out interface{}) error {
+	return forceApi.GetSObjectsContext(context.Background(), ids, fields, out)
+}
+
+// GetSObjectsContext is like GetSObjects but carries ctx to the underlying http requests.
+func (forceApi *ForceApi) GetSObjectsContext(ctx context.Context, ids []string, fields []string, out interface{}) error {
+	slice := reflect.ValueOf(out)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("Unable to retrieve records into %T, expected a pointer to a slice", out)
+	}
+	slice = slice.Elem()
+
+	elemType := slice.Type().Elem()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	sobject, ok := reflect.New(elemType).Interface().(SObject)
+	if !ok {
+		return fmt.Errorf("Unable to retrieve records into %T, %v is not an SObject", out, elemType)
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("Unable to retrieve records without fields")
+	}
+
+	uri := fmt.Sprintf(collectionsUri+"/%v", forceApi.apiVersion, sobject.APIName())
+
+	for start := 0; start < len(ids); start += MaxCollectionSize {
+		end := collectionChunkEnd(start, len(ids))
+
+		params := url.Values{
+			"ids":    {strings.Join(ids[start:end], ",")},
+			"fields": {strings.Join(fields, ",")},
+		}
+
+		chunk := reflect.New(slice.Type())
+		if err := forceApi.collectionRequest(ctx, "GET", uri, params, nil, chunk.Interface()); err != nil {
+			return err
+		}
+
+		slice.Set(reflect.AppendSlice(slice, chunk.Elem()))
+	}
+
+	return nil
+}
+
+// writeSObjects sends in to uri in chunks of MaxCollectionSize records.
+func (forceApi *ForceApi) writeSObjects(ctx context.Context, method, uri string, allOrNone bool, in []SObject) ([]*SObjectResponse, error) {
+	resps := make([]*SObjectResponse, 0, len(in))
+	for start := 0; start < len(in); start += MaxCollectionSize {
+		end := collectionChunkEnd(start, len(in))
+
+		payload := &collectionRequestBody{AllOrNone: allOrNone}
+		for _, record := range in[start:end] {
+			raw, err := collectionRecord(record)
+			if err != nil {
+				return resps, err
+			}
+			payload.Records = append(payload.Records, raw)
+		}
+
+		chunk := []*SObjectResponse{}
+		if err := forceApi.collectionRequest(ctx, method, uri, nil, payload, &chunk); err != nil {
+			return resps, err
+		}
+
+		resps = append(resps, chunk...)
+		if err := checkCollectionChunk(allOrNone, start, end, chunk); err != nil {
+			return resps, err
+		}
+	}
+
+	return resps, nil
+}
+
+// collectionRequest is like request but doesn't mistake an array of force.com
+// api errors for an array of results, which both decode into a slice.
+func (forceApi *ForceApi) collectionRequest(ctx context.Context, method, uri string, params url.Values, payload, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		jsonBytes, err := forcejson.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("Error marshaling encoded payload: %v", err)
+		}
+		body = bytes.NewReader(jsonBytes)
+	}
+
+	_, respBytes, err := forceApi.requestRaw(ctx, method, uri, params, contentType, responseType, body)
+	if err != nil {
+		return err
+	}
+
+	if err := forcejson.Unmarshal(respBytes, out); err != nil {
+		return fmt.Errorf("Unable to unmarshal response to object: %v", err)
+	}
+
+	return nil
+}
+
+// collectionRecord marshals in along with the attributes force.com needs to
+// tell the type of each record in a collection.
+func collectionRecord(in SObject) (*forcejson.RawMessage, error) {
+	data, err := forcejson.Marshal(in)
+	if err != nil {
+		return nil, fmt.Errorf("Error marshaling %v record: %v", in.APIName(), err)
+	}
+
+	fields := map[string]*forcejson.RawMessage{}
+	if err := forcejson.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("Error marshaling %v record: %v", in.APIName(), err)
+	}
+
+	if fields["attributes"], err = marshalRaw(map[string]string{"type": in.APIName()}); err != nil {
+		return nil, err
+	}
+
+	return marshalRaw(fields)
+}
+
+// checkCollectionChunk stops an allOrNone write once a chunk has been rolled back.
+func checkCollectionChunk(allOrNone bool, start, end int, chunk []*SObjectResponse) error {
+	if !allOrNone {
+		return nil
+	}
+
+	var failed *SObjectResponse
+	for _, resp := range chunk {
+		if resp.Success {
+			continue
+		}
+		// Report the record that caused the rollback rather than one that was
+		// merely rolled back along with it.
+		if failed == nil || (isRolledBack(failed.Errors) && !isRolledBack(resp.Errors)) {
+			failed = resp
+		}
+	}
+	if failed == nil {
+		return nil
+	}
+
+	return fmt.Errorf("sObject collection request for records %v to %v was rolled back: %v", start, end-1, failed.Errors)
+}
+
+func isRolledBack(errs ApiErrors) bool {
+	for _, err := range errs {
+		if err.StatusCode == allOrNoneRolledBack {
+			return true
+		}
+	}
+	return false
+}
+
+func collectionChunkEnd(start, length int) int {
+	if end := start + MaxCollectionSize; end < length {
+		return end
+	}
+	return length
+}
@@ -0,0 +1,145 @@
+package force_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/forcefakes"
+)
+
+func collectionResults(ids ...string) string {
+	results := make([]string, len(ids))
+	for i, id := range ids {
+		results[i] = fmt.Sprintf(`{"id": "%v", "success": true, "errors": []}`, id)
+	}
+	return "[" + strings.Join(results, ",") + "]"
+}
+
+var _ = Describe("Collections", func() {
+	var httpClient forcefakes.FakeHttpClient
+	var forceApi *force.ForceApi
+
+	BeforeEach(func() {
+		httpClient = forcefakes.FakeHttpClient{}
+
+		var err error
+		forceApi, err = createForceApi(&httpClient)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should insert records along with their type", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`[
+			{"id": "001A", "success": true, "errors": []},
+			{"success": false, "errors": [{"statusCode": "REQUIRED_FIELD_MISSING", "message": "Required fields are missing: [Name]", "fields": ["Name"]}]}
+		]`, 200), nil)
+
+		resps, err := forceApi.InsertSObjects(false, []force.SObject{
+			&CompositeSObject{Name: "Acme"},
+			&CompositeSObject{},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resps).To(HaveLen(2))
+		Expect(resps[0].Success).To(BeTrue())
+		Expect(resps[0].Id).To(Equal("001A"))
+		Expect(resps[1].Success).To(BeFalse())
+		Expect(resps[1].Errors[0].StatusCode).To(Equal("REQUIRED_FIELD_MISSING"))
+
+		req := httpClient.DoArgsForCall(3)
+		Expect(req.Method).To(Equal("POST"))
+		Expect(req.URL.Path).To(HaveSuffix("/composite/sobjects"))
+		body, _ := ioutil.ReadAll(req.Body)
+		Expect(body).To(MatchJSON(`{"allOrNone": false, "records": [
+			{"attributes": {"type": "APIName"}, "Name": "Acme"},
+			{"attributes": {"type": "APIName"}}
+		]}`))
+	})
+
+	It("should send more than 200 records in several requests", func() {
+		in := make([]force.SObject, force.MaxCollectionSize+1)
+		ids := make([]string, len(in))
+		for i := range in {
+			ids[i] = fmt.Sprintf("id%v", i)
+			in[i] = &CompositeSObject{Id: ids[i]}
+		}
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(collectionResults(ids[:force.MaxCollectionSize]...), 200), nil)
+		httpClient.DoReturnsOnCall(4, NewFakeResponse(collectionResults(ids[force.MaxCollectionSize:]...), 200), nil)
+
+		resps, err := forceApi.UpdateSObjects(true, in)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resps).To(HaveLen(len(in)))
+		Expect(resps[force.MaxCollectionSize].Id).To(Equal(ids[force.MaxCollectionSize]))
+		Expect(httpClient.DoCallCount()).To(Equal(5))
+		Expect(httpClient.DoArgsForCall(4).Method).To(Equal("PATCH"))
+	})
+
+	It("should stop after a rolled back allOrNone request", func() {
+		in := make([]force.SObject, force.MaxCollectionSize+1)
+		for i := range in {
+			in[i] = &CompositeSObject{Name: "Acme"}
+		}
+		results := make([]string, force.MaxCollectionSize)
+		for i := range results {
+			results[i] = `{"success": false, "errors": [{"statusCode": "ALL_OR_NONE_OPERATION_ROLLED_BACK", "message": "Record rolled back because not all records were valid and the request was using AllOrNone header"}]}`
+		}
+		results[1] = `{"success": false, "errors": [{"statusCode": "DUPLICATE_VALUE", "message": "duplicate value found"}]}`
+		httpClient.DoReturnsOnCall(3, NewFakeResponse("["+strings.Join(results, ",")+"]", 200), nil)
+
+		resps, err := forceApi.InsertSObjects(true, in)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("DUPLICATE_VALUE"))
+		Expect(resps).To(HaveLen(force.MaxCollectionSize))
+		Expect(httpClient.DoCallCount()).To(Equal(4))
+	})
+
+	It("should upsert records by external id", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`[{"id": "001A", "success": true, "created": true, "errors": []}]`, 200), nil)
+
+		resps, err := forceApi.UpsertSObjectsByExternalId(false, "Ext__c", []force.SObject{&CompositeSObject{Name: "Acme"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resps[0].Created).To(BeTrue())
+		Expect(httpClient.DoArgsForCall(3).URL.Path).To(HaveSuffix("/composite/sobjects/APIName/Ext__c"))
+	})
+
+	It("should delete records by id", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(collectionResults("001A", "001B"), 200), nil)
+
+		resps, err := forceApi.DeleteSObjects(true, []string{"001A", "001B"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resps).To(HaveLen(2))
+
+		req := httpClient.DoArgsForCall(3)
+		Expect(req.Method).To(Equal("DELETE"))
+		Expect(req.URL.Query().Get("ids")).To(Equal("001A,001B"))
+		Expect(req.URL.Query().Get("allOrNone")).To(Equal("true"))
+	})
+
+	It("should retrieve records aligned with their ids", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`[{"Id": "001A", "Name": "Acme"}, null]`, 200), nil)
+
+		out := []*CompositeSObject{}
+		err := forceApi.GetSObjects([]string{"001A", "001B"}, []string{"Id", "Name"}, &out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(HaveLen(2))
+		Expect(out[0].Name).To(Equal("Acme"))
+		Expect(out[1]).To(BeNil())
+
+		req := httpClient.DoArgsForCall(3)
+		Expect(req.URL.Path).To(HaveSuffix("/composite/sobjects/APIName"))
+		Expect(req.URL.Query().Get("fields")).To(Equal("Id,Name"))
+	})
+
+	It("should return force.com api errors", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`[{"errorCode": "INVALID_FIELD", "message": "No such column"}]`, 400), nil)
+
+		_, err := forceApi.DeleteSObjects(false, []string{"001A"})
+		var apiErrors force.ApiErrors
+		Expect(errors.As(err, &apiErrors)).To(BeTrue())
+		Expect(apiErrors[0].ErrorCode).To(Equal("INVALID_FIELD"))
+	})
+})
@@ -0,0 +1,263 @@
+package force
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/opendoor-labs/go-force/forcejson"
+)
+
+const (
+	compositeUri = resourcesUri + "/composite"
+
+	// MaxCompositeSubrequests is the most subrequests force.com accepts in a
+	// single composite request.
+	MaxCompositeSubrequests = 25
+)
+
+// CompositeReference returns a reference to a field of the result of an
+// earlier subrequest, e.g. CompositeReference("newAccount", "id") yields
+// "@{newAccount.id}". References can be used in SObject fields and ids.
+func CompositeReference(referenceId, field string) string {
+	return fmt.Sprintf("@{%v.%v}", referenceId, field)
+}
+
+// CompositeRequest queues subrequests that are executed by force.com in a
+// single round trip using the composite resource. Later subrequests can use
+// the results of earlier ones through CompositeReference.
+//
+//	composite := forceApi.NewCompositeRequest(true)
+//	composite.InsertSObject("newAccount", account)
+//	opportunity.AccountId = force.CompositeReference("newAccount", "id")
+//	composite.InsertSObject("newOpportunity", opportunity)
+//	resp, err := composite.Send()
+type CompositeRequest struct {
+	compositeBuilder
+	allOrNone bool
+}
+
+// compositeBuilder queues the subrequests of a composite request or graph.
+type compositeBuilder struct {
+	forceApi    *ForceApi
+	subrequests []*compositeSubrequest
+}
+
+type compositeSubrequest struct {
+	Method      string            `force:"method"`
+	Url         string            `force:"url"`
+	ReferenceId string            `force:"referenceId"`
+	Body        interface{}       `force:"body,omitempty"`
+	HttpHeaders map[string]string `force:"httpHeaders,omitempty"`
+
+	out interface{}
+}
+
+type compositeRequestBody struct {
+	AllOrNone        bool                   `force:"allOrNone"`
+	CompositeRequest []*compositeSubrequest `force:"compositeRequest"`
+}
+
+// CompositeResponse holds the outcome of every subrequest, in the order they
+// were queued.
+type CompositeResponse struct {
+	Responses []*CompositeSubresponse `force:"compositeResponse"`
+}
+
+// CompositeSubresponse is the outcome of a single subrequest. Errors is set
+// when the subrequest failed, which includes subrequests that were rolled
+// back because another one failed while allOrNone was set.
+type CompositeSubresponse struct {
+	ReferenceId    string               `force:"referenceId"`
+	HttpStatusCode int                  `force:"httpStatusCode"`
+	HttpHeaders    map[string]string    `force:"httpHeaders"`
+	Body           forcejson.RawMessage `force:"body"`
+	Errors         ApiErrors            `force:"-"`
+}
+
+// NewCompositeRequest starts an empty composite request. When allOrNone is
+// set, a failing subrequest rolls back all the others.
+func (forceApi *ForceApi) NewCompositeRequest(allOrNone bool) *CompositeRequest {
+	return &CompositeRequest{compositeBuilder: compositeBuilder{forceApi: forceApi}, allOrNone: allOrNone}
+}
+
+// Add queues an arbitrary subrequest. path is relative to the instance url,
+// e.g. "/services/data/v40.0/sobjects/Account". When the subrequest succeeds
+// its body is unmarshalled into out, if out is non-nil.
+func (c *compositeBuilder) Add(referenceId, method, path string, body, out interface{}) {
+	c.subrequests = append(c.subrequests, &compositeSubrequest{
+		Method:      method,
+		Url:         path,
+		ReferenceId: referenceId,
+		Body:        body,
+		out:         out,
+	})
+}
+
+// Len returns the number of subrequests queued so far.
+func (c *compositeBuilder) Len() int {
+	return len(c.subrequests)
+}
+
+// InsertSObject queues the creation of in. The id of the new record can be
+// referenced as CompositeReference(referenceId, "id").
+func (c *compositeBuilder) InsertSObject(referenceId string, in SObject) {
+	uri := c.forceApi.sObjectUrl(in.APIName(), sObjectKey)
+	c.Add(referenceId, "POST", uri, in, nil)
+}
+
+// UpdateSObject queues an update of the record with the given id, which may
+// be a CompositeReference.
+func (c *compositeBuilder) UpdateSObject(referenceId, id string, in SObject) {
+	uri := strings.Replace(c.forceApi.sObjectUrl(in.APIName(), rowTemplateKey), idKey, id, 1)
+	c.Add(referenceId, "PATCH", uri, in, nil)
+}
+
+// UpsertSObjectByExternalId queues an upsert of in keyed on an external id.
+func (c *compositeBuilder) UpsertSObjectByExternalId(referenceId, externalKey, externalId string, in SObject) {
+	uri := fmt.Sprintf("%v/%v/%v", c.forceApi.sObjectUrl(in.APIName(), sObjectKey), externalKey, externalId)
+	c.Add(referenceId, "PATCH", uri, in, nil)
+}
+
+// DeleteSObject queues the deletion of the record with the given id.
+func (c *compositeBuilder) DeleteSObject(referenceId, id string, in SObject) {
+	uri := strings.Replace(c.forceApi.sObjectUrl(in.APIName(), rowTemplateKey), idKey, id, 1)
+	c.Add(referenceId, "DELETE", uri, nil, nil)
+}
+
+// GetSObject queues the retrieval of the record with the given id into out.
+func (c *compositeBuilder) GetSObject(referenceId, id string, fields []string, out SObject) {
+	uri := strings.Replace(c.forceApi.sObjectUrl(out.APIName(), rowTemplateKey), idKey, id, 1)
+	if len(fields) > 0 {
+		uri += "?" + url.Values{"fields": {strings.Join(fields, ",")}}.Encode()
+	}
+	c.Add(referenceId, "GET", uri, nil, out)
+}
+
+// Query queues a SOQL query whose response is unmarshalled into out.
+// Records can be referenced as CompositeReference(referenceId, "records[0].Id").
+func (c *compositeBuilder) Query(referenceId, query string, out interface{}) {
+	uri := c.forceApi.resourceUrl(queryKey) + "?" + url.Values{"q": {query}}.Encode()
+	c.Add(referenceId, "GET", uri, nil, out)
+}
+
+// Send executes the queued subrequests. The returned error only reports
+// problems with the composite request as a whole; check each subresponse's
+// Errors for the outcome of individual subrequests.
+func (c *CompositeRequest) Send() (*CompositeResponse, error) {
+	return c.SendContext(context.Background())
+}
+
+// SendContext is like Send but carries ctx to the underlying http request.
+func (c *CompositeRequest) SendContext(ctx context.Context) (*CompositeResponse, error) {
+	if len(c.subrequests) == 0 {
+		return nil, fmt.Errorf("Composite request has no subrequests")
+	}
+	if len(c.subrequests) > MaxCompositeSubrequests {
+		return nil, fmt.Errorf("Composite request has %v subrequests, at most %v are allowed", len(c.subrequests), MaxCompositeSubrequests)
+	}
+	if err := checkReferenceIds(c.subrequests); err != nil {
+		return nil, err
+	}
+
+	payload := &compositeRequestBody{
+		AllOrNone:        c.allOrNone,
+		CompositeRequest: c.subrequests,
+	}
+
+	resp := &CompositeResponse{}
+	uri := fmt.Sprintf(compositeUri, c.forceApi.apiVersion)
+	if err := c.forceApi.PostContext(ctx, uri, nil, payload, resp); err != nil {
+		return nil, err
+	}
+
+	if err := resp.decode(c.subrequests); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// decode matches subresponses with the subrequests they answer and decodes
+// each of them.
+func (r *CompositeResponse) decode(subrequests []*compositeSubrequest) error {
+	outs := make(map[string]interface{}, len(subrequests))
+	for _, subrequest := range subrequests {
+		outs[subrequest.ReferenceId] = subrequest.out
+	}
+
+	for _, subresponse := range r.Responses {
+		if err := subresponse.decode(outs[subresponse.ReferenceId]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkReferenceIds makes sure every subrequest has a reference id of its
+// own, since subresponses are matched with their subrequest through it.
+func checkReferenceIds(subrequests []*compositeSubrequest) error {
+	seen := make(map[string]bool, len(subrequests))
+	for _, subrequest := range subrequests {
+		if seen[subrequest.ReferenceId] {
+			return fmt.Errorf("Reference id %v is used by more than one subrequest", subrequest.ReferenceId)
+		}
+		seen[subrequest.ReferenceId] = true
+	}
+	return nil
+}
+
+// Get returns the subresponse with the given reference id, or nil.
+func (r *CompositeResponse) Get(referenceId string) *CompositeSubresponse {
+	for _, subresponse := range r.Responses {
+		if subresponse.ReferenceId == referenceId {
+			return subresponse
+		}
+	}
+	return nil
+}
+
+// HasErrors reports whether any subrequest failed.
+func (r *CompositeResponse) HasErrors() bool {
+	for _, subresponse := range r.Responses {
+		if subresponse.Errors.Validate() {
+			return true
+		}
+	}
+	return false
+}
+
+// Decode unmarshals the body of a successful subresponse into out.
+func (r *CompositeSubresponse) Decode(out interface{}) error {
+	if r.Errors.Validate() {
+		return r.Errors
+	}
+	if len(r.Body) == 0 || string(r.Body) == "null" {
+		return nil
+	}
+	return forcejson.Unmarshal(r.Body, out)
+}
+
+// decode fills in Errors for failed subresponses and otherwise unmarshals the
+// body into out, if given.
+func (r *CompositeSubresponse) decode(out interface{}) error {
+	if r.HttpStatusCode >= http.StatusBadRequest {
+		apiErrors := ApiErrors{}
+		if err := forcejson.Unmarshal(r.Body, &apiErrors); err != nil || !apiErrors.Validate() {
+			apiErrors = ApiErrors{&ApiError{Message: string(r.Body)}}
+		}
+		r.Errors = apiErrors
+		return nil
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := r.Decode(out); err != nil {
+		return fmt.Errorf("Unable to unmarshal subresponse %v: %v", r.ReferenceId, err)
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package force
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	compositeGraphUri = compositeUri + "/graph"
+
+	// MaxCompositeGraphNodes is the most nodes force.com accepts in a single
+	// graph.
+	MaxCompositeGraphNodes = 500
+
+	processingHalted = "PROCESSING_HALTED"
+)
+
+// CompositeGraphRequest holds one or more graphs that are executed by
+// force.com in a single round trip using the composite graph resource. Each
+// graph is a transaction of its own: it either succeeds as a whole or is
+// rolled back as a whole, independently of the other graphs. Graphs allow far
+// more nodes than the 25 subrequests of a CompositeRequest.
+//
+//	graphs := forceApi.NewCompositeGraphRequest()
+//	graph := graphs.Graph("account1")
+//	graph.InsertSObject("newAccount", account)
+//	contact.AccountId = force.CompositeReference("newAccount", "id")
+//	graph.InsertSObject("newContact", contact)
+//	resp, err := graphs.Send()
+type CompositeGraphRequest struct {
+	forceApi *ForceApi
+	graphs   []*CompositeGraph
+}
+
+// CompositeGraph is a named set of nodes. Nodes are queued like the
+// subrequests of a CompositeRequest, but only sobject operations are
+// available since graphs reject the others, such as queries. Nodes may
+// reference the results of other nodes of the same graph through
+// CompositeReference.
+type CompositeGraph struct {
+	builder compositeBuilder
+	graphId string
+}
+
+type compositeGraphRequestBody struct {
+	Graphs []*compositeGraphBody `force:"graphs"`
+}
+
+type compositeGraphBody struct {
+	GraphId          string                 `force:"graphId"`
+	CompositeRequest []*compositeSubrequest `force:"compositeRequest"`
+}
+
+// CompositeGraphResponse holds the outcome of every graph, in the order they
+// were added.
+type CompositeGraphResponse struct {
+	Graphs []*CompositeGraphResult `force:"graphs"`
+}
+
+// CompositeGraphResult is the outcome of a single graph. When IsSuccessful
+// is false the whole graph was rolled back and GraphResponse holds the error
+// of every node.
+type CompositeGraphResult struct {
+	GraphId       string            `force:"graphId"`
+	IsSuccessful  bool              `force:"isSuccessful"`
+	GraphResponse CompositeResponse `force:"graphResponse"`
+}
+
+// NewCompositeGraphRequest starts a composite graph request without graphs.
+func (forceApi *ForceApi) NewCompositeGraphRequest() *CompositeGraphRequest {
+	return &CompositeGraphRequest{forceApi: forceApi}
+}
+
+// Graph returns the graph with the given id, adding it if needed.
+func (c *CompositeGraphRequest) Graph(graphId string) *CompositeGraph {
+	for _, graph := range c.graphs {
+		if graph.graphId == graphId {
+			return graph
+		}
+	}
+
+	graph := &CompositeGraph{builder: compositeBuilder{forceApi: c.forceApi}, graphId: graphId}
+	c.graphs = append(c.graphs, graph)
+	return graph
+}
+
+// Id returns the id the graph was created with.
+func (g *CompositeGraph) Id() string {
+	return g.graphId
+}
+
+// Len returns the number of nodes queued so far.
+func (g *CompositeGraph) Len() int {
+	return g.builder.Len()
+}
+
+// InsertSObject queues the creation of in. The id of the new record can be
+// referenced as CompositeReference(referenceId, "id").
+func (g *CompositeGraph) InsertSObject(referenceId string, in SObject) {
+	g.builder.InsertSObject(referenceId, in)
+}
+
+// UpdateSObject queues an update of the record with the given id, which may
+// be a CompositeReference.
+func (g *CompositeGraph) UpdateSObject(referenceId, id string, in SObject) {
+	g.builder.UpdateSObject(referenceId, id, in)
+}
+
+// UpsertSObjectByExternalId queues an upsert of in keyed on an external id.
+func (g *CompositeGraph) UpsertSObjectByExternalId(referenceId, externalKey, externalId string, in SObject) {
+	g.builder.UpsertSObjectByExternalId(referenceId, externalKey, externalId, in)
+}
+
+// DeleteSObject queues the deletion of the record with the given id.
+func (g *CompositeGraph) DeleteSObject(referenceId, id string, in SObject) {
+	g.builder.DeleteSObject(referenceId, id, in)
+}
+
+// GetSObject queues the retrieval of the record with the given id into out.
+func (g *CompositeGraph) GetSObject(referenceId, id string, fields []string, out SObject) {
+	g.builder.GetSObject(referenceId, id, fields, out)
+}
+
+// Send executes every graph. The returned error only reports problems with
+// the request as a whole; check each graph's IsSuccessful and Errors for the
+// outcome of individual graphs.
+func (c *CompositeGraphRequest) Send() (*CompositeGraphResponse, error) {
+	return c.SendContext(context.Background())
+}
+
+// SendContext is like Send but carries ctx to the underlying http request.
+func (c *CompositeGraphRequest) SendContext(ctx context.Context) (*CompositeGraphResponse, error) {
+	if len(c.graphs) == 0 {
+		return nil, fmt.Errorf("Composite graph request has no graphs")
+	}
+
+	payload := &compositeGraphRequestBody{}
+	subrequests := make(map[string][]*compositeSubrequest, len(c.graphs))
+	for _, graph := range c.graphs {
+		nodes := graph.builder.subrequests
+		if len(nodes) == 0 {
+			return nil, fmt.Errorf("Composite graph %v has no nodes", graph.graphId)
+		}
+		if len(nodes) > MaxCompositeGraphNodes {
+			return nil, fmt.Errorf("Composite graph %v has %v nodes, at most %v are allowed", graph.graphId, len(nodes), MaxCompositeGraphNodes)
+		}
+		if err := checkReferenceIds(nodes); err != nil {
+			return nil, fmt.Errorf("Composite graph %v: %v", graph.graphId, err)
+		}
+
+		payload.Graphs = append(payload.Graphs, &compositeGraphBody{
+			GraphId:          graph.graphId,
+			CompositeRequest: nodes,
+		})
+		subrequests[graph.graphId] = nodes
+	}
+
+	resp := &CompositeGraphResponse{}
+	uri := fmt.Sprintf(compositeGraphUri, c.forceApi.apiVersion)
+	if err := c.forceApi.PostContext(ctx, uri, nil, payload, resp); err != nil {
+		return nil, err
+	}
+
+	for _, graph := range resp.Graphs {
+		if err := graph.GraphResponse.decode(subrequests[graph.GraphId]); err != nil {
+			return resp, fmt.Errorf("Composite graph %v: %v", graph.GraphId, err)
+		}
+	}
+
+	return resp, nil
+}
+
+// Get returns the result of the graph with the given id, or nil.
+func (r *CompositeGraphResponse) Get(graphId string) *CompositeGraphResult {
+	for _, graph := range r.Graphs {
+		if graph.GraphId == graphId {
+			return graph
+		}
+	}
+	return nil
+}
+
+// IsSuccessful reports whether every graph succeeded.
+func (r *CompositeGraphResponse) IsSuccessful() bool {
+	for _, graph := range r.Graphs {
+		if !graph.IsSuccessful {
+			return false
+		}
+	}
+	return true
+}
+
+// FailedNode returns the node that caused the graph to be rolled back, or nil
+// if the graph succeeded. The other nodes of a failed graph only report that
+// their processing was halted.
+func (r *CompositeGraphResult) FailedNode() *CompositeSubresponse {
+	if r.IsSuccessful {
+		return nil
+	}
+
+	var failed *CompositeSubresponse
+	for _, node := range r.GraphResponse.Responses {
+		if !node.Errors.Validate() {
+			continue
+		}
+		if !isProcessingHalted(node.Errors) {
+			return node
+		}
+		if failed == nil {
+			failed = node
+		}
+	}
+
+	return failed
+}
+
+// Errors returns the errors of the node that caused the graph to be rolled
+// back, or nil if the graph succeeded.
+func (r *CompositeGraphResult) Errors() ApiErrors {
+	if node := r.FailedNode(); node != nil {
+		return node.Errors
+	}
+	return nil
+}
+
+func isProcessingHalted(errs ApiErrors) bool {
+	for _, err := range errs {
+		if err.ErrorCode == processingHalted {
+			return true
+		}
+	}
+	return false
+}
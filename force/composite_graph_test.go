@@ -0,0 +1,93 @@
+package force_test
+
+import (
+	"io/ioutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/forcefakes"
+)
+
+var _ = Describe("CompositeGraph", func() {
+	var httpClient forcefakes.FakeHttpClient
+	var forceApi *force.ForceApi
+
+	BeforeEach(func() {
+		httpClient = forcefakes.FakeHttpClient{}
+
+		var err error
+		forceApi, err = createForceApi(&httpClient)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should send every graph in one round trip and report each outcome", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`{"graphs": [
+			{"graphId": "g1", "isSuccessful": true, "graphResponse": {"compositeResponse": [
+				{"referenceId": "parent", "httpStatusCode": 201, "httpHeaders": {}, "body": {"id": "001A", "success": true, "errors": []}},
+				{"referenceId": "child", "httpStatusCode": 201, "httpHeaders": {}, "body": {"id": "006A", "success": true, "errors": []}}
+			]}},
+			{"graphId": "g2", "isSuccessful": false, "graphResponse": {"compositeResponse": [
+				{"referenceId": "parent", "httpStatusCode": 400, "httpHeaders": {}, "body": [{"errorCode": "PROCESSING_HALTED", "message": "The transaction was rolled back since another operation in the same transaction failed."}]},
+				{"referenceId": "child", "httpStatusCode": 400, "httpHeaders": {}, "body": [{"errorCode": "REQUIRED_FIELD_MISSING", "message": "Required fields are missing: [Name]", "fields": ["Name"]}]}
+			]}}
+		]}`, 200), nil)
+
+		graphs := forceApi.NewCompositeGraphRequest()
+		first := graphs.Graph("g1")
+		first.InsertSObject("parent", &CompositeSObject{Name: "Acme"})
+		first.InsertSObject("child", &CompositeSObject{Name: "Deal", AccountId: force.CompositeReference("parent", "id")})
+		second := graphs.Graph("g2")
+		second.InsertSObject("parent", &CompositeSObject{Name: "Globex"})
+		second.InsertSObject("child", &CompositeSObject{AccountId: force.CompositeReference("parent", "id")})
+		Expect(graphs.Graph("g1")).To(BeIdenticalTo(first))
+
+		resp, err := graphs.Send()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.IsSuccessful()).To(BeFalse())
+
+		Expect(resp.Get("g1").IsSuccessful).To(BeTrue())
+		Expect(resp.Get("g1").FailedNode()).To(BeNil())
+		created := force.SObjectResponse{}
+		Expect(resp.Get("g1").GraphResponse.Get("child").Decode(&created)).To(Succeed())
+		Expect(created.Id).To(Equal("006A"))
+
+		Expect(resp.Get("g2").FailedNode().ReferenceId).To(Equal("child"))
+		Expect(resp.Get("g2").Errors()[0].ErrorCode).To(Equal("REQUIRED_FIELD_MISSING"))
+
+		req := httpClient.DoArgsForCall(3)
+		Expect(req.URL.Path).To(HaveSuffix("/composite/graph"))
+		body, _ := ioutil.ReadAll(req.Body)
+		Expect(body).To(MatchJSON(`{"graphs": [
+			{"graphId": "g1", "compositeRequest": [
+				{"method": "POST", "url": "the/url", "referenceId": "parent", "body": {"Name": "Acme"}},
+				{"method": "POST", "url": "the/url", "referenceId": "child", "body": {"Name": "Deal", "AccountId": "@{parent.id}"}}
+			]},
+			{"graphId": "g2", "compositeRequest": [
+				{"method": "POST", "url": "the/url", "referenceId": "parent", "body": {"Name": "Globex"}},
+				{"method": "POST", "url": "the/url", "referenceId": "child", "body": {"AccountId": "@{parent.id}"}}
+			]}
+		]}`))
+	})
+
+	It("should refuse graphs without nodes", func() {
+		graphs := forceApi.NewCompositeGraphRequest()
+		graphs.Graph("empty")
+
+		_, err := graphs.Send()
+		Expect(err).To(HaveOccurred())
+		Expect(httpClient.DoCallCount()).To(Equal(3))
+	})
+
+	It("should refuse nodes sharing a reference id", func() {
+		graphs := forceApi.NewCompositeGraphRequest()
+		graph := graphs.Graph("g1")
+		graph.InsertSObject("account", &CompositeSObject{Name: "Acme"})
+		graph.InsertSObject("account", &CompositeSObject{Name: "Globex"})
+
+		_, err := graphs.Send()
+		Expect(err).To(MatchError(ContainSubstring("account")))
+		Expect(httpClient.DoCallCount()).To(Equal(3))
+	})
+})
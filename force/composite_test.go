@@ -0,0 +1,107 @@
+package force_test
+
+import (
+	"io/ioutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/forcefakes"
+)
+
+type CompositeSObject struct {
+	Id        string `force:",omitempty"`
+	Name      string `force:",omitempty"`
+	AccountId string `force:",omitempty"`
+}
+
+func (c *CompositeSObject) APIName() string {
+	return "APIName"
+}
+
+var _ = Describe("Composite", func() {
+	var httpClient forcefakes.FakeHttpClient
+	var forceApi *force.ForceApi
+
+	BeforeEach(func() {
+		httpClient = forcefakes.FakeHttpClient{}
+
+		var err error
+		forceApi, err = createForceApi(&httpClient)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should send every subrequest in one round trip", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`{"compositeResponse": [
+			{"referenceId": "parent", "httpStatusCode": 201, "httpHeaders": {"Location": "/the/url/001A"}, "body": {"id": "001A", "success": true, "errors": []}},
+			{"referenceId": "child", "httpStatusCode": 201, "httpHeaders": {}, "body": {"id": "006A", "success": true, "errors": []}},
+			{"referenceId": "fetch", "httpStatusCode": 200, "httpHeaders": {}, "body": {"Id": "006A", "Name": "Deal"}}
+		]}`, 200), nil)
+
+		composite := forceApi.NewCompositeRequest(true)
+		composite.InsertSObject("parent", &CompositeSObject{Name: "Acme"})
+		composite.InsertSObject("child", &CompositeSObject{Name: "Deal", AccountId: force.CompositeReference("parent", "id")})
+		fetched := &CompositeSObject{}
+		composite.GetSObject("fetch", force.CompositeReference("child", "id"), []string{"Id", "Name"}, fetched)
+		Expect(composite.Len()).To(Equal(3))
+
+		resp, err := composite.Send()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.HasErrors()).To(BeFalse())
+		Expect(fetched.Name).To(Equal("Deal"))
+
+		created := force.SObjectResponse{}
+		Expect(resp.Get("parent").Decode(&created)).To(Succeed())
+		Expect(created.Id).To(Equal("001A"))
+		Expect(resp.Get("parent").HttpStatusCode).To(Equal(201))
+		Expect(resp.Get("parent").HttpHeaders["Location"]).To(Equal("/the/url/001A"))
+
+		req := httpClient.DoArgsForCall(3)
+		Expect(req.URL.Path).To(HaveSuffix("/composite"))
+		body, _ := ioutil.ReadAll(req.Body)
+		Expect(body).To(MatchJSON(`{"allOrNone": true, "compositeRequest": [
+			{"method": "POST", "url": "the/url", "referenceId": "parent", "body": {"Name": "Acme"}},
+			{"method": "POST", "url": "the/url", "referenceId": "child", "body": {"Name": "Deal", "AccountId": "@{parent.id}"}},
+			{"method": "GET", "url": "sobjects-resources/APIName/@{child.id}?fields=Id%2CName", "referenceId": "fetch"}
+		]}`))
+	})
+
+	It("should report the errors of failed subrequests", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`{"compositeResponse": [
+			{"referenceId": "parent", "httpStatusCode": 400, "httpHeaders": {}, "body": [{"errorCode": "PROCESSING_HALTED", "message": "The transaction was rolled back"}]},
+			{"referenceId": "child", "httpStatusCode": 400, "httpHeaders": {}, "body": [{"errorCode": "REQUIRED_FIELD_MISSING", "message": "Required fields are missing: [Name]", "fields": ["Name"]}]}
+		]}`, 200), nil)
+
+		composite := forceApi.NewCompositeRequest(true)
+		composite.InsertSObject("parent", &CompositeSObject{Name: "Acme"})
+		composite.InsertSObject("child", &CompositeSObject{})
+
+		resp, err := composite.Send()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.HasErrors()).To(BeTrue())
+		Expect(resp.Get("child").Errors[0].ErrorCode).To(Equal("REQUIRED_FIELD_MISSING"))
+		Expect(resp.Get("child").Decode(&force.SObjectResponse{})).To(HaveOccurred())
+	})
+
+	It("should refuse more subrequests than force.com allows", func() {
+		composite := forceApi.NewCompositeRequest(false)
+		for i := 0; i <= force.MaxCompositeSubrequests; i++ {
+			composite.DeleteSObject("ref", "001A", &CompositeSObject{})
+		}
+
+		_, err := composite.Send()
+		Expect(err).To(HaveOccurred())
+		Expect(httpClient.DoCallCount()).To(Equal(3))
+	})
+
+	It("should refuse subrequests sharing a reference id", func() {
+		composite := forceApi.NewCompositeRequest(false)
+		composite.DeleteSObject("ref", "001A", &CompositeSObject{})
+		composite.DeleteSObject("ref", "001B", &CompositeSObject{})
+
+		_, err := composite.Send()
+		Expect(err).To(MatchError(ContainSubstring("ref")))
+		Expect(httpClient.DoCallCount()).To(Equal(3))
+	})
+})
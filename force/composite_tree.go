@@ -0,0 +1,304 @@
+package force
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/opendoor-labs/go-force/forcejson"
+)
+
+const (
+	compositeTreeUri = compositeUri + "/tree/%v"
+
+	// MaxSObjectTreeRecords is the most records, parents and children
+	// together, force.com accepts in a single composite tree request.
+	MaxSObjectTreeRecords = 200
+)
+
+type sObjectTreeRequestBody struct {
+	Records []*forcejson.RawMessage `force:"records"`
+}
+
+type sObjectTreeResponse struct {
+	HasErrors bool                 `force:"hasErrors"`
+	Results   []*sObjectTreeResult `force:"results"`
+}
+
+type sObjectTreeResult struct {
+	ReferenceId string    `force:"referenceId"`
+	Id          string    `force:"id"`
+	Errors      ApiErrors `force:"errors"`
+}
+
+// SObjectTreeErrors is returned by InsertSObjectTree when force.com rejected
+// the tree. It maps the reference ids of the failing records to their errors.
+type SObjectTreeErrors map[string]ApiErrors
+
+func (e SObjectTreeErrors) Error() string {
+	referenceIds := make([]string, 0, len(e))
+	for referenceId := range e {
+		referenceIds = append(referenceIds, referenceId)
+	}
+	sort.Strings(referenceIds)
+
+	s := make([]string, len(referenceIds))
+	for i, referenceId := range referenceIds {
+		s[i] = fmt.Sprintf("%v: %v", referenceId, e[referenceId])
+	}
+
+	return strings.Join(s, "\n")
+}
+
+// InsertSObjectTree creates records of a single type along with their
+// children in a single request using the composite tree resource. Children
+// are taken from the slice fields of each record whose elements are SObjects
+// and whose name, from the force tag or the field name, is the name of a
+// child relationship of the record's type, e.g.
+//
+//	type AccountTree struct {
+//		sobjects.Account
+//		Contacts []*sobjects.Contact `force:",omitempty"`
+//	}
+//
+// Child relationships are looked up with DescribeSObject. Up to 200 records
+// can be created at once. The whole tree is rolled back if any record fails,
+// in which case the error is an SObjectTreeErrors.
+//
+// Records are given reference ids depth first, parents before their
+// children: "ref1" is the first record, "ref2" its first child if it has
+// any, and so on. The returned map holds the id of every created record
+// keyed by its reference id.
+func (forceApi *ForceApi) InsertSObjectTree(in []SObject) (map[string]string, error) {
+	return forceApi.InsertSObjectTreeContext(context.Background(), in)
+}
+
+// InsertSObjectTreeContext is like InsertSObjectTree but carries ctx to the underlying http requests.
+func (forceApi *ForceApi) InsertSObjectTreeContext(ctx context.Context, in []SObject) (map[string]string, error) {
+	if len(in) == 0 {
+		return map[string]string{}, nil
+	}
+
+	apiName := in[0].APIName()
+	for _, record := range in {
+		if record.APIName() != apiName {
+			return nil, fmt.Errorf("Unable to insert trees of different types: %v and %v", apiName, record.APIName())
+		}
+	}
+
+	builder := &sObjectTreeBuilder{ctx: ctx, forceApi: forceApi}
+	payload := &sObjectTreeRequestBody{}
+	for _, record := range in {
+		raw, err := builder.record(record)
+		if err != nil {
+			return nil, err
+		}
+		payload.Records = append(payload.Records, raw)
+	}
+	if builder.count > MaxSObjectTreeRecords {
+		return nil, fmt.Errorf("Composite tree has %v records, at most %v are allowed", builder.count, MaxSObjectTreeRecords)
+	}
+
+	body, err := forcejson.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("Error marshaling encoded payload: %v", err)
+	}
+
+	uri := fmt.Sprintf(compositeTreeUri, forceApi.apiVersion, apiName)
+	resp, respBytes, err := forceApi.requestRaw(ctx, "POST", uri, nil, contentType, responseType, bytes.NewReader(body))
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusBadRequest) {
+		return nil, err
+	}
+
+	result := &sObjectTreeResponse{}
+	if unmarshalErr := forcejson.Unmarshal(respBytes, result); unmarshalErr != nil {
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("Unable to unmarshal response to object: %v", unmarshalErr)
+	}
+
+	if result.HasErrors {
+		treeErrors := SObjectTreeErrors{}
+		for _, r := range result.Results {
+			treeErrors[r.ReferenceId] = r.Errors
+		}
+		return nil, treeErrors
+	}
+
+	ids := make(map[string]string, len(result.Results))
+	for _, r := range result.Results {
+		ids[r.ReferenceId] = r.Id
+	}
+
+	return ids, nil
+}
+
+// sObjectTreeBuilder marshals records along with their children, numbering
+// them as it goes.
+type sObjectTreeBuilder struct {
+	ctx      context.Context
+	forceApi *ForceApi
+	count    int
+}
+
+type sObjectTreeAttributes struct {
+	Type        string `force:"type"`
+	ReferenceId string `force:"referenceId"`
+}
+
+type sObjectTreeChildren struct {
+	Records []*forcejson.RawMessage `force:"records"`
+}
+
+func (b *sObjectTreeBuilder) record(in SObject) (*forcejson.RawMessage, error) {
+	b.count++
+	referenceId := fmt.Sprintf("ref%v", b.count)
+
+	data, err := forcejson.Marshal(in)
+	if err != nil {
+		return nil, fmt.Errorf("Error marshaling %v record: %v", in.APIName(), err)
+	}
+
+	fields := map[string]*forcejson.RawMessage{}
+	if err := forcejson.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("Error marshaling %v record: %v", in.APIName(), err)
+	}
+
+	attributes, err := marshalRaw(&sObjectTreeAttributes{Type: in.APIName(), ReferenceId: referenceId})
+	if err != nil {
+		return nil, err
+	}
+	fields["attributes"] = attributes
+
+	for _, child := range sObjectTreeChildFields(reflect.ValueOf(in)) {
+		// The slice was marshalled like any other field; it is replaced by
+		// the children wrapped the way force.com expects them.
+		delete(fields, child.name)
+		if len(child.records) == 0 {
+			continue
+		}
+
+		relationshipName, err := b.relationshipName(in, child.name)
+		if err != nil {
+			return nil, err
+		}
+
+		children := &sObjectTreeChildren{}
+		for _, record := range child.records {
+			raw, err := b.record(record)
+			if err != nil {
+				return nil, err
+			}
+			children.Records = append(children.Records, raw)
+		}
+
+		if fields[relationshipName], err = marshalRaw(children); err != nil {
+			return nil, err
+		}
+	}
+
+	return marshalRaw(fields)
+}
+
+// relationshipName returns the name of the child relationship of parent
+// matching the field name, as spelled by force.com.
+func (b *sObjectTreeBuilder) relationshipName(parent SObject, name string) (string, error) {
+	desc, err := b.forceApi.DescribeSObjectContext(b.ctx, parent)
+	if err != nil {
+		return "", err
+	}
+
+	for _, relationship := range desc.ChildRelationsips {
+		if strings.EqualFold(relationship.RelationshipName, name) {
+			return relationship.RelationshipName, nil
+		}
+	}
+
+	return "", fmt.Errorf("Unable to find child relationship %v of %v", name, parent.APIName())
+}
+
+type sObjectTreeChildField struct {
+	name    string
+	records []SObject
+}
+
+var sObjectType = reflect.TypeOf((*SObject)(nil)).Elem()
+
+// sObjectTreeChildFields returns the slices of SObjects held by the struct v
+// points to, including those of embedded structs.
+func sObjectTreeChildFields(v reflect.Value) []sObjectTreeChildField {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var children []sObjectTreeChildField
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("force")
+		if tag == "-" {
+			continue
+		}
+
+		if sf.Anonymous {
+			children = append(children, sObjectTreeChildFields(v.Field(i))...)
+			continue
+		}
+		if sf.PkgPath != "" || sf.Type.Kind() != reflect.Slice {
+			continue
+		}
+
+		elemType := sf.Type.Elem()
+		if !elemType.Implements(sObjectType) && !reflect.PtrTo(elemType).Implements(sObjectType) {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			name = sf.Name
+		}
+
+		slice := v.Field(i)
+		records := make([]SObject, 0, slice.Len())
+		for j := 0; j < slice.Len(); j++ {
+			elem := slice.Index(j)
+			if elem.Kind() == reflect.Interface {
+				elem = elem.Elem()
+			}
+			if elem.Kind() == reflect.Struct && elem.CanAddr() {
+				elem = elem.Addr()
+			}
+			if !elem.IsValid() || (elem.Kind() == reflect.Ptr && elem.IsNil()) {
+				continue
+			}
+			if record, ok := elem.Interface().(SObject); ok {
+				records = append(records, record)
+			}
+		}
+
+		children = append(children, sObjectTreeChildField{name: name, records: records})
+	}
+
+	return children
+}
+
+func marshalRaw(v interface{}) (*forcejson.RawMessage, error) {
+	data, err := forcejson.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("Error marshaling encoded payload: %v", err)
+	}
+
+	raw := forcejson.RawMessage(data)
+	return &raw, nil
+}
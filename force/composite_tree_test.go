@@ -0,0 +1,87 @@
+package force_test
+
+import (
+	"io/ioutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/forcefakes"
+)
+
+type TreeSObject struct {
+	CompositeSObject
+	Children []*CompositeSObject `force:"children,omitempty"`
+}
+
+var _ = Describe("CompositeTree", func() {
+	var httpClient forcefakes.FakeHttpClient
+	var forceApi *force.ForceApi
+
+	BeforeEach(func() {
+		httpClient = forcefakes.FakeHttpClient{}
+
+		var err error
+		forceApi, err = createForceApi(&httpClient)
+		Expect(err).NotTo(HaveOccurred())
+
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`{"name": "APIName", "childRelationships": [
+			{"childSObject": "APIName", "field": "ParentId", "relationshipName": "Children"}
+		]}`, 200), nil)
+	})
+
+	It("should insert records along with their children", func() {
+		httpClient.DoReturnsOnCall(4, NewFakeResponse(`{"hasErrors": false, "results": [
+			{"referenceId": "ref1", "id": "001A"},
+			{"referenceId": "ref2", "id": "001B"},
+			{"referenceId": "ref3", "id": "001C"}
+		]}`, 201), nil)
+
+		ids, err := forceApi.InsertSObjectTree([]force.SObject{
+			&TreeSObject{
+				CompositeSObject: CompositeSObject{Name: "Parent"},
+				Children:         []*CompositeSObject{{Name: "Child"}},
+			},
+			&TreeSObject{CompositeSObject: CompositeSObject{Name: "Lonely"}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ids).To(Equal(map[string]string{"ref1": "001A", "ref2": "001B", "ref3": "001C"}))
+		Expect(httpClient.DoCallCount()).To(Equal(5))
+
+		req := httpClient.DoArgsForCall(4)
+		Expect(req.URL.Path).To(HaveSuffix("/composite/tree/APIName"))
+		body, _ := ioutil.ReadAll(req.Body)
+		Expect(body).To(MatchJSON(`{"records": [
+			{"attributes": {"type": "APIName", "referenceId": "ref1"}, "Name": "Parent", "Children": {"records": [
+				{"attributes": {"type": "APIName", "referenceId": "ref2"}, "Name": "Child"}
+			]}},
+			{"attributes": {"type": "APIName", "referenceId": "ref3"}, "Name": "Lonely"}
+		]}`))
+	})
+
+	It("should report the errors of the failing records", func() {
+		httpClient.DoReturnsOnCall(4, NewFakeResponse(`{"hasErrors": true, "results": [
+			{"referenceId": "ref2", "errors": [{"statusCode": "INVALID_EMAIL_ADDRESS", "message": "Email: invalid email address: 123", "fields": ["Email"]}]}
+		]}`, 400), nil)
+
+		_, err := forceApi.InsertSObjectTree([]force.SObject{
+			&TreeSObject{Children: []*CompositeSObject{{Name: "Child"}}},
+		})
+		Expect(err).To(BeAssignableToTypeOf(force.SObjectTreeErrors{}))
+		Expect(err.(force.SObjectTreeErrors)["ref2"][0].StatusCode).To(Equal("INVALID_EMAIL_ADDRESS"))
+	})
+
+	It("should refuse children without a matching relationship", func() {
+		type Orphans struct {
+			CompositeSObject
+			Orphans []CompositeSObject
+		}
+
+		_, err := forceApi.InsertSObjectTree([]force.SObject{
+			&Orphans{Orphans: []CompositeSObject{{Name: "Child"}}},
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(httpClient.DoCallCount()).To(Equal(4))
+	})
+})
@@ -0,0 +1,136 @@
+package force
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const duplicateRuleHeader = "Sforce-Duplicate-Rule-Header"
+
+// DuplicateResult describes the records a duplicate rule matched when it
+// blocked a save.
+type DuplicateResult struct {
+	AllowSave               bool                    `json:"allowSave" force:"allowSave"`
+	DuplicateRule           string                  `json:"duplicateRule" force:"duplicateRule"`
+	DuplicateRuleEntityType string                  `json:"duplicateRuleEntityType" force:"duplicateRuleEntityType"`
+	ErrorMessage            string                  `json:"errorMessage" force:"errorMessage"`
+	MatchResults            []*DuplicateMatchResult `json:"matchResults" force:"matchResults"`
+}
+
+// DuplicateMatchResult lists the records one matching rule of a duplicate
+// rule matched.
+type DuplicateMatchResult struct {
+	EntityType   string                  `json:"entityType" force:"entityType"`
+	MatchEngine  string                  `json:"matchEngine" force:"matchEngine"`
+	Rule         string                  `json:"rule" force:"rule"`
+	Size         int                     `json:"size" force:"size"`
+	Success      bool                    `json:"success" force:"success"`
+	Errors       ApiErrors               `json:"errors" force:"errors"`
+	MatchRecords []*DuplicateMatchRecord `json:"matchRecords" force:"matchRecords"`
+}
+
+// DuplicateMatchRecord is a record matched by a matching rule.
+type DuplicateMatchRecord struct {
+	// MatchConfidence is between 0 and 100, 100 being an exact match.
+	MatchConfidence float64                `json:"matchConfidence" force:"matchConfidence"`
+	FieldDiffs      []*DuplicateFieldDiff  `json:"fieldDiffs" force:"fieldDiffs"`
+	Record          map[string]interface{} `json:"record" force:"record"`
+}
+
+// DuplicateFieldDiff compares a field of the saved record with the matched
+// one. Difference is one of "SAME", "DIFFERENT" and "NULL".
+type DuplicateFieldDiff struct {
+	Name       string `json:"name" force:"name"`
+	Difference string `json:"difference" force:"difference"`
+}
+
+// Id returns the id of the matched record.
+func (r *DuplicateMatchRecord) Id() string {
+	id, _ := r.Record["Id"].(string)
+	return id
+}
+
+// MatchedIds returns the ids of every matched record, in order of the
+// matching rules.
+func (r *DuplicateResult) MatchedIds() []string {
+	var ids []string
+	for _, matchResult := range r.MatchResults {
+		for _, matchRecord := range matchResult.MatchRecords {
+			if id := matchRecord.Id(); id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	return ids
+}
+
+// DuplicateResultOf returns the duplicate result of the first force.com api
+// error of err that has one, or nil when err wasn't raised by a duplicate
+// rule.
+func DuplicateResultOf(err error) *DuplicateResult {
+	apiErrors, _ := errorDetails(err)
+	for _, apiError := range apiErrors {
+		if apiError.DuplicateResult != nil {
+			return apiError.DuplicateResult
+		}
+	}
+
+	return nil
+}
+
+// DuplicateRuleHeader controls how duplicate rules treat saves, through the
+// Sforce-Duplicate-Rule-Header.
+type DuplicateRuleHeader struct {
+	// AllowSave saves records even though duplicate rules would block them.
+	AllowSave bool
+	// IncludeRecordDetails returns all fields of the matched records instead
+	// of just their ids.
+	IncludeRecordDetails bool
+	// RunAsCurrentUser enforces the sharing rules of the current user when
+	// looking for duplicates.
+	RunAsCurrentUser bool
+}
+
+func (h DuplicateRuleHeader) String() string {
+	return fmt.Sprintf("allowSave=%t; includeRecordDetails=%t; runAsCurrentUser=%t", h.AllowSave, h.IncludeRecordDetails, h.RunAsCurrentUser)
+}
+
+// WithDuplicateRuleHeader sends h with every api request.
+func WithDuplicateRuleHeader(h DuplicateRuleHeader) Option {
+	return WithHeader(duplicateRuleHeader, h.String())
+}
+
+// ContextWithDuplicateRuleHeader returns a copy of ctx that sends h with the
+// requests it is given to, for instance to save a record a duplicate rule
+// blocked:
+//
+//	_, err := forceApi.InsertSObject(account)
+//	if force.IsDuplicate(err) && !mergeInto(force.DuplicateResultOf(err).MatchedIds()) {
+//		ctx := force.ContextWithDuplicateRuleHeader(ctx, force.DuplicateRuleHeader{AllowSave: true})
+//		_, err = forceApi.InsertSObjectContext(ctx, account)
+//	}
+func ContextWithDuplicateRuleHeader(ctx context.Context, h DuplicateRuleHeader) context.Context {
+	return ContextWithHeader(ctx, duplicateRuleHeader, h.String())
+}
+
+type headerContextKey struct{}
+
+// ContextWithHeader returns a copy of ctx that adds a header to the api
+// requests it is given to, on top of those set with WithHeader. Headers set
+// by ForceApi itself, such as Authorization, can't be overridden.
+func ContextWithHeader(ctx context.Context, key, value string) context.Context {
+	header := http.Header{}
+	for k, values := range contextHeader(ctx) {
+		header[k] = append([]string(nil), values...)
+	}
+	header.Add(key, value)
+
+	return context.WithValue(ctx, headerContextKey{}, header)
+}
+
+func contextHeader(ctx context.Context) http.Header {
+	header, _ := ctx.Value(headerContextKey{}).(http.Header)
+	return header
+}
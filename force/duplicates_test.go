@@ -0,0 +1,101 @@
+package force_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/forcefakes"
+)
+
+const duplicatesDetected = `{
+	"message": "Use one of these records?",
+	"errorCode": "DUPLICATES_DETECTED",
+	"fields": [],
+	"duplicateResult": {
+		"allowSave": true,
+		"duplicateRule": "Standard_Account_Duplicate_Rule",
+		"duplicateRuleEntityType": "Account",
+		"errorMessage": "You're creating a duplicate record.",
+		"matchResults": [{
+			"entityType": "Account",
+			"errors": [],
+			"matchEngine": "FuzzyMatchEngine",
+			"matchRecords": [
+				{"additionalInformation": [], "fieldDiffs": [{"name": "Name", "difference": "SAME"}], "matchConfidence": 88.5, "record": {"attributes": {"type": "Account"}, "Id": "001A"}},
+				{"additionalInformation": [], "fieldDiffs": [], "matchConfidence": 75, "record": {"attributes": {"type": "Account"}, "Id": "001B"}}
+			],
+			"rule": "Standard_Account_Match_Rule_v1_0",
+			"size": 2,
+			"success": true
+		}]
+	}
+}`
+
+var _ = Describe("Duplicate rules", func() {
+	var (
+		httpClient forcefakes.FakeHttpClient
+		forceApi   *force.ForceApi
+	)
+
+	BeforeEach(func() {
+		httpClient = forcefakes.FakeHttpClient{}
+
+		var err error
+		forceApi, err = createForceApi(&httpClient)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should decode the duplicate result and allow saving", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`[`+duplicatesDetected+`]`, 400), nil)
+		httpClient.DoReturnsOnCall(4, NewFakeResponse(`{"id": "001C", "success": true, "errors": []}`, 201), nil)
+
+		_, err := forceApi.InsertSObject(&CompositeSObject{Name: "Acme"})
+		Expect(force.IsDuplicate(err)).To(BeTrue())
+
+		result := force.DuplicateResultOf(err)
+		Expect(result).NotTo(BeNil())
+		Expect(result.AllowSave).To(BeTrue())
+		Expect(result.DuplicateRule).To(Equal("Standard_Account_Duplicate_Rule"))
+		Expect(result.MatchedIds()).To(Equal([]string{"001A", "001B"}))
+
+		matchResult := result.MatchResults[0]
+		Expect(matchResult.Rule).To(Equal("Standard_Account_Match_Rule_v1_0"))
+		Expect(matchResult.MatchRecords[0].MatchConfidence).To(Equal(88.5))
+		Expect(matchResult.MatchRecords[0].FieldDiffs[0]).To(Equal(&force.DuplicateFieldDiff{Name: "Name", Difference: "SAME"}))
+		Expect(httpClient.DoArgsForCall(3).Header.Get("Sforce-Duplicate-Rule-Header")).To(BeEmpty())
+
+		ctx := force.ContextWithDuplicateRuleHeader(context.Background(), force.DuplicateRuleHeader{AllowSave: true})
+		resp, err := forceApi.InsertSObjectContext(ctx, &CompositeSObject{Name: "Acme"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Id).To(Equal("001C"))
+		Expect(httpClient.DoArgsForCall(4).Header.Get("Sforce-Duplicate-Rule-Header")).To(Equal("allowSave=true; includeRecordDetails=false; runAsCurrentUser=false"))
+	})
+
+	It("should decode duplicate results of collections", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`[{"success": false, "errors": [`+duplicatesDetected+`]}]`, 200), nil)
+
+		results, err := forceApi.InsertSObjects(false, []force.SObject{&CompositeSObject{Name: "Acme"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(force.IsDuplicate(results[0].Errors)).To(BeTrue())
+		Expect(force.DuplicateResultOf(results[0].Errors).MatchedIds()).To(Equal([]string{"001A", "001B"}))
+	})
+
+	It("should send the header with every request", func() {
+		httpClient := forcefakes.FakeHttpClient{}
+		httpClient.DoReturnsOnCall(0, NewFakeResponse(`{"records": []}`, 200), nil)
+
+		forceApi, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithAccessToken("id", "token", "https://example.my.salesforce.com"),
+			force.WithLazyMetadata(),
+			force.WithDuplicateRuleHeader(force.DuplicateRuleHeader{AllowSave: true, RunAsCurrentUser: true}),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(forceApi.Query("SELECT Id FROM Account", &map[string]interface{}{})).To(Succeed())
+		Expect(httpClient.DoArgsForCall(0).Header.Get("Sforce-Duplicate-Rule-Header")).To(Equal("allowSave=true; includeRecordDetails=false; runAsCurrentUser=true"))
+	})
+})
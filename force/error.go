@@ -1,7 +1,9 @@
 package force
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 )
 
@@ -14,6 +16,12 @@ type ApiError struct {
 	ErrorCode        string   `json:"errorCode,omitempty" force:"errorCode,omitempty"`
 	ErrorName        string   `json:"error,omitempty" force:"error,omitempty"`
 	ErrorDescription string   `json:"error_description,omitempty" force:"error_description,omitempty"`
+	// StatusCode is used instead of ErrorCode by per-record errors, such as
+	// those of sObject Collections.
+	StatusCode string `json:"statusCode,omitempty" force:"statusCode,omitempty"`
+	// DuplicateResult is set for DUPLICATES_DETECTED errors raised by
+	// duplicate rules.
+	DuplicateResult *DuplicateResult `json:"duplicateResult,omitempty" force:"duplicateResult,omitempty"`
 }
 
 func (e ApiErrors) Error() string {
@@ -37,6 +45,22 @@ func (e ApiErrors) Validate() bool {
 	return false
 }
 
+// Is reports whether e is of the kind of target, like RequestError.Is.
+func (e ApiErrors) Is(target error) bool {
+	return isKind(e, target)
+}
+
+// As sets target, a **ApiError, to the first of e.
+func (e ApiErrors) As(target interface{}) bool {
+	apiError, ok := target.(**ApiError)
+	if !ok || len(e) == 0 {
+		return false
+	}
+
+	*apiError = e[0]
+	return true
+}
+
 func (e ApiError) Error() string {
 	return e.String()
 }
@@ -46,7 +70,7 @@ func (e ApiError) String() string {
 }
 
 func (e ApiError) Validate() bool {
-	if len(e.Fields) != 0 || len(e.Message) != 0 || len(e.ErrorCode) != 0 || len(e.ErrorName) != 0 || len(e.ErrorDescription) != 0 {
+	if len(e.Fields) != 0 || len(e.Message) != 0 || len(e.ErrorCode) != 0 || len(e.ErrorName) != 0 || len(e.ErrorDescription) != 0 || len(e.StatusCode) != 0 || e.DuplicateResult != nil {
 		return true
 	}
 
@@ -54,10 +78,198 @@ func (e ApiError) Validate() bool {
 }
 
 func WasNotFound(err error) (bool, error) {
-	apiErrors, ok := err.(ApiErrors)
-	if !ok || len(apiErrors) > 1 || len(apiErrors) < 1 {
+	apiErrors, _ := errorDetails(err)
+	if len(apiErrors) != 1 {
 		return false, err
 	}
 
 	return apiErrors[0].ErrorCode == "NOT_FOUND", nil
 }
+
+// RequestError is returned for requests force.com answered with an error
+// that carries no force.com api errors, and, when the ForceApi was built
+// WithRequestErrors, for all of them. It keeps the details of the request
+// along with the force.com api errors, if any. Use errors.As to get at it,
+// or at its ApiErrors, and errors.Is with ErrNotFound and friends to test its
+// kind:
+//
+//	var reqErr *force.RequestError
+//	if errors.As(err, &reqErr) && reqErr.StatusCode == http.StatusForbidden {
+//		...
+//	}
+//	if errors.Is(err, force.ErrRowLock) {
+//		...
+//	}
+type RequestError struct {
+	StatusCode int
+	Method     string
+	// Path is the path of the request, without its query parameters.
+	Path string
+	// Body is the raw response body.
+	Body   []byte
+	Errors ApiErrors
+}
+
+func (e *RequestError) Error() string {
+	if len(e.Errors) != 0 {
+		return e.Errors.Error()
+	}
+
+	return fmt.Sprintf("Unexpected status code %v for %v request: %s", e.StatusCode, e.Method, e.Body)
+}
+
+// Unwrap returns the force.com api errors, if any.
+func (e *RequestError) Unwrap() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+
+	return e.Errors
+}
+
+// Is reports whether e is of the kind of target, one of ErrNotFound,
+// ErrDuplicate, ErrValidation, ErrRowLock, ErrRateLimited and
+// ErrSessionExpired.
+func (e *RequestError) Is(target error) bool {
+	return isKind(e, target)
+}
+
+// As sets target, a **ApiError, to the first force.com api error.
+func (e *RequestError) As(target interface{}) bool {
+	apiError, ok := target.(**ApiError)
+	if !ok || len(e.Errors) == 0 {
+		return false
+	}
+
+	*apiError = e.Errors[0]
+	return true
+}
+
+// Kinds of errors tested for with errors.Is, or with the predicate of the
+// same name, such as IsNotFound.
+var (
+	ErrNotFound       = errors.New("force: not found")
+	ErrDuplicate      = errors.New("force: duplicate")
+	ErrValidation     = errors.New("force: validation error")
+	ErrRowLock        = errors.New("force: unable to lock row")
+	ErrRateLimited    = errors.New("force: rate limited")
+	ErrSessionExpired = errors.New("force: session expired")
+)
+
+// Error codes of each kind of error.
+var (
+	NotFoundErrorCodes       = []string{"NOT_FOUND", "ENTITY_IS_DELETED"}
+	DuplicateErrorCodes      = []string{"DUPLICATE_VALUE", "DUPLICATES_DETECTED", "DUPLICATE_EXTERNAL_ID", "DUPLICATE_USERNAME"}
+	ValidationErrorCodes     = []string{"FIELD_CUSTOM_VALIDATION_EXCEPTION", "REQUIRED_FIELD_MISSING", "INVALID_FIELD", "INVALID_FIELD_FOR_INSERT_UPDATE", "INVALID_TYPE_ON_FIELD_IN_RECORD", "INVALID_OR_NULL_FOR_RESTRICTED_PICKLIST", "INVALID_EMAIL_ADDRESS", "STRING_TOO_LONG", "NUMBER_OUTSIDE_VALID_RANGE", "FIELD_INTEGRITY_EXCEPTION", "MALFORMED_ID", "INVALID_CROSS_REFERENCE_KEY"}
+	RowLockErrorCodes        = []string{"UNABLE_TO_LOCK_ROW"}
+	RateLimitedErrorCodes    = []string{"REQUEST_LIMIT_EXCEEDED", "TOO_MANY_REQUESTS"}
+	SessionExpiredErrorCodes = []string{invalidSessionErrorCode}
+)
+
+type errorKind struct {
+	err        error
+	codes      *[]string
+	statusCode int
+}
+
+var (
+	notFoundKind       = errorKind{ErrNotFound, &NotFoundErrorCodes, http.StatusNotFound}
+	duplicateKind      = errorKind{ErrDuplicate, &DuplicateErrorCodes, 0}
+	validationKind     = errorKind{ErrValidation, &ValidationErrorCodes, 0}
+	rowLockKind        = errorKind{ErrRowLock, &RowLockErrorCodes, 0}
+	rateLimitedKind    = errorKind{ErrRateLimited, &RateLimitedErrorCodes, http.StatusTooManyRequests}
+	sessionExpiredKind = errorKind{ErrSessionExpired, &SessionExpiredErrorCodes, 0}
+
+	errorKinds = []errorKind{notFoundKind, duplicateKind, validationKind, rowLockKind, rateLimitedKind, sessionExpiredKind}
+)
+
+// matches reports whether err is of this kind: either one of its force.com
+// api errors has one of the kind's error codes, or, lacking api errors, the
+// response had the kind's status code.
+func (k errorKind) matches(err error) bool {
+	apiErrors, statusCode := errorDetails(err)
+	if len(apiErrors) == 0 {
+		return k.statusCode != 0 && statusCode == k.statusCode
+	}
+
+	for _, apiError := range apiErrors {
+		for _, code := range *k.codes {
+			if apiError.ErrorCode == code || apiError.StatusCode == code {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isKind reports whether err is of the kind of target, one of the errors of
+// errorKinds.
+func isKind(err, target error) bool {
+	for _, kind := range errorKinds {
+		if target == kind.err {
+			return kind.matches(err)
+		}
+	}
+
+	return false
+}
+
+// errorDetails finds the force.com api errors and the status code of err,
+// looking through wrapped errors.
+func errorDetails(err error) (ApiErrors, int) {
+	for err != nil {
+		switch e := err.(type) {
+		case *RequestError:
+			return e.Errors, e.StatusCode
+		case ApiErrors:
+			return e, 0
+		case *ApiError:
+			return ApiErrors{e}, 0
+		case ApiError:
+			return ApiErrors{&e}, 0
+		}
+
+		wrapper, ok := err.(interface {
+			Unwrap() error
+		})
+		if !ok {
+			return nil, 0
+		}
+		err = wrapper.Unwrap()
+	}
+
+	return nil, 0
+}
+
+// IsNotFound reports whether err says the requested record doesn't exist.
+func IsNotFound(err error) bool {
+	return notFoundKind.matches(err)
+}
+
+// IsDuplicate reports whether err says the record duplicates another one.
+func IsDuplicate(err error) bool {
+	return duplicateKind.matches(err)
+}
+
+// IsValidationError reports whether err says the record is invalid, for
+// instance because of a validation rule or a missing required field.
+func IsValidationError(err error) bool {
+	return validationKind.matches(err)
+}
+
+// IsRowLock reports whether err says the record was locked by another
+// transaction. Such requests can be retried, see RetryPolicy.
+func IsRowLock(err error) bool {
+	return rowLockKind.matches(err)
+}
+
+// IsRateLimited reports whether err says the org ran out of api requests.
+func IsRateLimited(err error) bool {
+	return rateLimitedKind.matches(err)
+}
+
+// IsSessionExpired reports whether err says the access token expired.
+func IsSessionExpired(err error) bool {
+	return sessionExpiredKind.matches(err)
+}
@@ -4,6 +4,7 @@
 package force
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
@@ -19,110 +20,35 @@ const (
 	testEnvironment   = "production"
 )
 
+// Create authenticates with the OAuth username-password flow. It is kept for
+// compatibility; New with WithPassword is more flexible.
 func Create(version, clientId, clientSecret, userName, password, securityToken,
 	environment string, httpClient HttpClient) (*ForceApi, error) {
-	oauth := &forceOauth{
-		clientId:      clientId,
-		clientSecret:  clientSecret,
-		userName:      userName,
-		password:      password,
-		securityToken: securityToken,
-		environment:   environment,
-		httpClient:    httpClient,
-	}
-
-	forceApi := &ForceApi{
-		apiResources:           make(map[string]string),
-		apiSObjects:            make(map[string]*SObjectMetaData),
-		apiSObjectDescriptions: make(map[string]*SObjectDescription),
-		apiVersion:             version,
-		oauth:                  oauth,
-		httpClient:             httpClient,
-	}
-
-	// Init oauth
-	err := forceApi.oauth.Authenticate()
-	if err != nil {
-		return nil, err
-	}
-
-	// Init Api Resources
-	err = forceApi.getApiResources()
-	if err != nil {
-		return nil, err
-	}
-
-	err = forceApi.getApiSObjects()
-	if err != nil {
-		return nil, err
-	}
-
-	return forceApi, nil
+	return New(
+		WithVersion(version),
+		WithHttpClient(httpClient),
+		WithPassword(clientId, clientSecret, userName, password, securityToken),
+		WithEnvironment(environment),
+	)
 }
 
+// CreateWithAccessToken uses an access token obtained elsewhere. It is kept
+// for compatibility; New with WithAccessToken is more flexible.
 func CreateWithAccessToken(version, clientId, accessToken, instanceUrl string, httpClient HttpClient) (*ForceApi, error) {
-	oauth := &forceOauth{
-		clientId:    clientId,
-		AccessToken: accessToken,
-		InstanceUrl: instanceUrl,
-		httpClient:  httpClient,
-	}
-
-	forceApi := &ForceApi{
-		apiResources:           make(map[string]string),
-		apiSObjects:            make(map[string]*SObjectMetaData),
-		apiSObjectDescriptions: make(map[string]*SObjectDescription),
-		apiVersion:             version,
-		oauth:                  oauth,
-		httpClient:             httpClient,
-	}
-
-	// We need to check for oath correctness here, since we are not generating the token ourselves.
-	if err := forceApi.oauth.Validate(); err != nil {
-		return nil, err
-	}
-
-	// Init Api Resources
-	if err := forceApi.ResetResources(); err != nil {
-		return nil, err
-	}
-
-	return forceApi, nil
+	return New(
+		WithVersion(version),
+		WithHttpClient(httpClient),
+		WithAccessToken(clientId, accessToken, instanceUrl),
+	)
 }
 
+// CreateWithRefreshToken uses an access token obtained elsewhere, like
+// CreateWithAccessToken.
+//
+// Deprecated: CreateWithRefreshToken takes no refresh token, so it can't
+// renew the session and used to fail trying. Use New with WithRefreshToken.
 func CreateWithRefreshToken(version, clientId, accessToken, instanceUrl string, httpClient HttpClient) (*ForceApi, error) {
-	oauth := &forceOauth{
-		clientId:    clientId,
-		AccessToken: accessToken,
-		InstanceUrl: instanceUrl,
-		httpClient:  httpClient,
-	}
-
-	forceApi := &ForceApi{
-		apiResources:           make(map[string]string),
-		apiSObjects:            make(map[string]*SObjectMetaData),
-		apiSObjectDescriptions: make(map[string]*SObjectDescription),
-		apiVersion:             version,
-		oauth:                  oauth,
-		httpClient:             httpClient,
-	}
-
-	// obtain access token
-	if err := forceApi.RefreshToken(); err != nil {
-		return nil, err
-	}
-
-	// We need to check for oath correctness here, since we are not generating the token ourselves.
-	if err := forceApi.oauth.Validate(); err != nil {
-		return nil, err
-	}
-
-	// Init Api Resources
-	if err := forceApi.ResetResources(); err != nil {
-		return nil, err
-	}
-
-	return forceApi, nil
+	return CreateWithAccessToken(version, clientId, accessToken, instanceUrl, httpClient)
 }
 
 // Used when running tests.
@@ -137,11 +63,16 @@ func createTest() *ForceApi {
 }
 
 func (forceApi *ForceApi) ResetResources() error {
-	err := forceApi.getApiResources()
+	return forceApi.ResetResourcesContext(context.Background())
+}
+
+// ResetResourcesContext is like ResetResources but carries ctx to the underlying http requests.
+func (forceApi *ForceApi) ResetResourcesContext(ctx context.Context) error {
+	err := forceApi.getApiResources(ctx)
 	if err != nil {
 		return err
 	}
-	err = forceApi.getApiSObjects()
+	err = forceApi.getApiSObjects(ctx)
 	if err != nil {
 		return err
 	}
@@ -164,6 +95,9 @@ type ForceApiLogger interface {
 // can easily be written for other logging packages (e.g., the
 // golang-sanctioned glog framework).
 func (forceApi *ForceApi) TraceOn(prefix string, logger ForceApiLogger) {
+	forceApi.mu.Lock()
+	defer forceApi.mu.Unlock()
+
 	forceApi.logger = logger
 	if prefix == "" {
 		forceApi.logPrefix = prefix
@@ -174,13 +108,20 @@ func (forceApi *ForceApi) TraceOn(prefix string, logger ForceApiLogger) {
 
 // TraceOff turns off tracing. It is idempotent.
 func (forceApi *ForceApi) TraceOff() {
+	forceApi.mu.Lock()
+	defer forceApi.mu.Unlock()
+
 	forceApi.logger = nil
 	forceApi.logPrefix = ""
 }
 
 func (forceApi *ForceApi) trace(name string, value interface{}, format string) {
-	if forceApi.logger != nil {
+	forceApi.mu.RLock()
+	logger, logPrefix := forceApi.logger, forceApi.logPrefix
+	forceApi.mu.RUnlock()
+
+	if logger != nil {
 		logMsg := "%s%s " + format + "\n"
-		forceApi.logger.Printf(logMsg, forceApi.logPrefix, name, value)
+		logger.Printf(logMsg, logPrefix, name, value)
 	}
 }
@@ -12,7 +12,7 @@ import (
 	"github.com/opendoor-labs/go-force/force/forcefakes"
 )
 
-const FakeOauthRespBody = `{"access_token": "at", "instance_url": "iu", "id": "anid", "issued_at": "ia", "signature": "sig"}`
+const FakeOauthRespBody = `{"access_token": "at", "instance_url": "https://iu.my.salesforce.com", "id": "anid", "issued_at": "ia", "signature": "sig"}`
 
 func NewFakeResponse(body string, statusCode int) (resp *http.Response) {
 	return &http.Response{
@@ -0,0 +1,169 @@
+package force
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	jwtBearerGrantType = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+
+	loginAudience     = "https://login.salesforce.com"
+	testLoginAudience = "https://test.salesforce.com"
+
+	// jwtLifetime is how long a minted assertion is valid. force.com only
+	// checks it when the assertion is exchanged, so it can be short.
+	jwtLifetime = 3 * time.Minute
+)
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+type jwtClaims struct {
+	Iss string `json:"iss"`
+	Sub string `json:"sub"`
+	Aud string `json:"aud"`
+	Exp int64  `json:"exp"`
+}
+
+// WithJwtBearer authenticates with the OAuth JWT bearer flow: an assertion
+// for subject, the username of the integration user, is signed with key
+// using RS256 and exchanged for an access token. A new assertion is minted
+// whenever the session expires, so no password or refresh token needs to be
+// stored. audience defaults to https://login.salesforce.com, or
+// https://test.salesforce.com for the sandbox environment.
+func WithJwtBearer(clientId, subject, audience string, key crypto.Signer) Option {
+	return func(o *options) {
+		if err := checkRSASigner(key); err != nil {
+			o.err = err
+			return
+		}
+
+		o.clientId = clientId
+		o.tokenSource = func(o *options) TokenSource {
+			return &JwtTokenSource{
+				ClientId:   clientId,
+				Subject:    subject,
+				Audience:   audience,
+				Key:        key,
+				LoginUrl:   o.defaultLoginUrl(),
+				HttpClient: o.httpClient,
+			}
+		}
+	}
+}
+
+// WithJwtBearerPEM is like WithJwtBearer but takes a PEM encoded RSA private
+// key, in either PKCS #1 or PKCS #8 form.
+func WithJwtBearerPEM(clientId, subject, audience string, keyPEM []byte) Option {
+	key, err := parseRSAPrivateKeyPEM(keyPEM)
+	if err != nil {
+		return func(o *options) {
+			o.err = err
+		}
+	}
+
+	return WithJwtBearer(clientId, subject, audience, key)
+}
+
+// checkRSASigner makes sure key signs with RSA, the only algorithm force.com
+// accepts for assertions.
+func checkRSASigner(key crypto.Signer) error {
+	if key == nil {
+		return fmt.Errorf("Unable to sign JWT assertions without a private key")
+	}
+	if _, ok := key.Public().(*rsa.PublicKey); !ok {
+		return fmt.Errorf("Unable to use private key of type %T, expected an RSA key", key)
+	}
+	return nil
+}
+
+func parseRSAPrivateKeyPEM(keyPEM []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("Unable to decode private key: no PEM data found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse private key: %v", err)
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("Unable to use private key of type %T, expected an RSA key", key)
+	}
+
+	return rsaKey, nil
+}
+
+// authenticateJwt mints a new assertion and exchanges it for an access token.
+func (oauth *forceOauth) authenticateJwt(ctx context.Context) error {
+	assertion, err := oauth.jwtAssertion(time.Now())
+	if err != nil {
+		return err
+	}
+
+	payload := url.Values{
+		"grant_type": {jwtBearerGrantType},
+		"assertion":  {assertion},
+	}
+
+	return oauth.requestToken(ctx, payload)
+}
+
+// jwtAssertion returns a signed assertion valid from now.
+func (oauth *forceOauth) jwtAssertion(now time.Time) (string, error) {
+	audience := oauth.jwtAudience
+	if audience == "" {
+		audience = loginAudience
+		if strings.HasPrefix(oauth.tokenUrl(), testLoginAudience+"/") {
+			audience = testLoginAudience
+		}
+	}
+
+	if err := checkRSASigner(oauth.jwtSigner); err != nil {
+		return "", err
+	}
+
+	header, err := json.Marshal(&jwtHeader{Alg: "RS256", Typ: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(&jwtClaims{
+		Iss: oauth.clientId,
+		Sub: oauth.jwtSubject,
+		Aud: audience,
+		Exp: now.Add(jwtLifetime).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	signature, err := oauth.jwtSigner.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return "", fmt.Errorf("Unable to sign JWT assertion: %v", err)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,124 @@
+package force_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/forcefakes"
+)
+
+// verifyAssertion checks the signature of a JWT bearer assertion and returns
+// its claims.
+func verifyAssertion(req *http.Request, key *rsa.PrivateKey) map[string]interface{} {
+	body, _ := ioutil.ReadAll(req.Body)
+	form, _ := url.ParseQuery(string(body))
+	Expect(form.Get("grant_type")).To(Equal("urn:ietf:params:oauth:grant-type:jwt-bearer"))
+
+	parts := strings.Split(form.Get("assertion"), ".")
+	Expect(parts).To(HaveLen(3))
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	Expect(err).NotTo(HaveOccurred())
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	Expect(rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature)).To(Succeed())
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	Expect(err).NotTo(HaveOccurred())
+	claims := map[string]interface{}{}
+	Expect(json.Unmarshal(payload, &claims)).To(Succeed())
+	return claims
+}
+
+var _ = Describe("JwtBearer", func() {
+	var httpClient forcefakes.FakeHttpClient
+	var key *rsa.PrivateKey
+
+	BeforeEach(func() {
+		httpClient = forcefakes.FakeHttpClient{}
+
+		var err error
+		key, err = rsa.GenerateKey(rand.Reader, 1024)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should exchange a signed assertion for an access token", func() {
+		httpClient.DoReturnsOnCall(0, NewFakeResponse(FakeOauthRespBody, 200), nil)
+
+		forceApi, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithJwtBearer("client", "integration@example.com", "", key),
+			force.WithLoginUrl("https://example.my.salesforce.com"),
+			force.WithLazyMetadata(),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(forceApi.GetAccessToken()).To(Equal("at"))
+
+		req := httpClient.DoArgsForCall(0)
+		Expect(req.URL.String()).To(Equal("https://example.my.salesforce.com/services/oauth2/token"))
+		claims := verifyAssertion(req, key)
+		Expect(claims["iss"]).To(Equal("client"))
+		Expect(claims["sub"]).To(Equal("integration@example.com"))
+		Expect(claims["aud"]).To(Equal("https://login.salesforce.com"))
+		Expect(claims["exp"]).To(BeNumerically(">", 0))
+	})
+
+	It("should mint a new assertion when the session expires", func() {
+		keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+		httpClient.DoReturnsOnCall(0, NewFakeResponse(FakeOauthRespBody, 200), nil)
+		httpClient.DoReturnsOnCall(1, NewFakeResponse(`[{"errorCode": "INVALID_SESSION_ID", "message": "Session expired or invalid"}]`, 401), nil)
+		httpClient.DoReturnsOnCall(2, NewFakeResponse(`{"access_token": "renewed", "instance_url": "https://iu.my.salesforce.com"}`, 200), nil)
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`{"records": []}`, 200), nil)
+
+		forceApi, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithJwtBearerPEM("client", "integration@example.com", "https://test.salesforce.com", keyPEM),
+			force.WithLazyMetadata(),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(forceApi.Query("SELECT Id FROM Account", &map[string]interface{}{})).To(Succeed())
+		Expect(httpClient.DoCallCount()).To(Equal(4))
+		Expect(forceApi.GetAccessToken()).To(Equal("renewed"))
+
+		claims := verifyAssertion(httpClient.DoArgsForCall(2), key)
+		Expect(claims["aud"]).To(Equal("https://test.salesforce.com"))
+		Expect(httpClient.DoArgsForCall(3).Header.Get("Authorization")).To(Equal("Bearer renewed"))
+	})
+
+	It("should refuse a key that isn't PEM encoded", func() {
+		_, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithJwtBearerPEM("client", "integration@example.com", "", []byte("not a key")),
+		)
+		Expect(err).To(HaveOccurred())
+		Expect(httpClient.DoCallCount()).To(Equal(0))
+	})
+	It("should refuse a key that doesn't sign with RSA", func() {
+		ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithJwtBearer("client", "integration@example.com", "", ecKey),
+		)
+		Expect(err).To(MatchError(ContainSubstring("expected an RSA key")))
+		Expect(httpClient.DoCallCount()).To(Equal(0))
+	})
+})
@@ -1,5 +1,7 @@
 package force
 
+import "context"
+
 type Limits map[string]Limit
 
 type Limit struct {
@@ -8,10 +10,15 @@ type Limit struct {
 }
 
 func (forceApi *ForceApi) GetLimits() (limits *Limits, err error) {
-	uri := forceApi.apiResources[limitsKey]
+	return forceApi.GetLimitsContext(context.Background())
+}
+
+// GetLimitsContext is like GetLimits but carries ctx to the underlying http request.
+func (forceApi *ForceApi) GetLimitsContext(ctx context.Context) (limits *Limits, err error) {
+	uri := forceApi.resourceUrl(limitsKey)
 
 	limits = &Limits{}
-	_, err = forceApi.Get(uri, nil, limits)
+	_, err = forceApi.GetContext(ctx, uri, nil, limits)
 
 	return
 }
@@ -1,23 +1,36 @@
 package force
 
 import (
+	"context"
+	"crypto"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 )
 
 const (
-	grantType    = "password"
-	loginUri     = "https://login.salesforce.com/services/oauth2/token"
-	testLoginUri = "https://test.salesforce.com/services/oauth2/token"
+	grantType                  = "password"
+	refreshTokenGrantType      = "refresh_token"
+	clientCredentialsGrantType = "client_credentials"
+	loginBaseUri               = "https://login.salesforce.com"
+	testLoginBaseUri           = "https://test.salesforce.com"
+	tokenPath                  = "/services/oauth2/token"
+	revokePath                 = "/services/oauth2/revoke"
+	introspectPath             = "/services/oauth2/introspect"
+	userInfoPath               = "/services/oauth2/userinfo"
 
 	invalidSessionErrorCode = "INVALID_SESSION_ID"
 )
 
 type forceOauth struct {
+	// mu guards the token fields below, which change whenever a new access
+	// token is obtained.
+	mu          sync.RWMutex
 	AccessToken string `json:"access_token"`
 	InstanceUrl string `json:"instance_url"`
 	Id          string `json:"id"`
@@ -31,12 +44,39 @@ type forceOauth struct {
 	password      string
 	securityToken string
 	environment   string
-	httpClient    HttpClient
+	loginUrl      string
+	jwtSubject    string
+	jwtAudience   string
+	jwtSigner     crypto.Signer
+
+	clientCredentials bool
+	httpClient        HttpClient
+
+	// source, when set, provides the access tokens instead of the
+	// credentials above.
+	source TokenSource
+
+	// onTokenRefreshed, when set, is called with every new access token.
+	onTokenRefreshed func(token *Token)
+
+	// authMu makes sure only one new access token is obtained at a time.
+	authMu sync.Mutex
 }
 
 func (oauth *forceOauth) Validate() error {
-	if oauth == nil || len(oauth.InstanceUrl) == 0 || len(oauth.AccessToken) == 0 {
-		return fmt.Errorf("Invalid Force Oauth Object: %#v", oauth)
+	if oauth == nil {
+		return fmt.Errorf("Invalid Force Oauth Object: not authenticated")
+	}
+
+	oauth.mu.RLock()
+	defer oauth.mu.RUnlock()
+
+	// The message leaves out the object itself, which holds the credentials.
+	if len(oauth.InstanceUrl) == 0 {
+		return fmt.Errorf("Invalid Force Oauth Object: missing instance url")
+	}
+	if len(oauth.AccessToken) == 0 {
+		return fmt.Errorf("Invalid Force Oauth Object: missing access token")
 	}
 
 	return nil
@@ -53,6 +93,73 @@ func (oauth *forceOauth) Expired(apiErrors ApiErrors) bool {
 }
 
 func (oauth *forceOauth) Authenticate() error {
+	return oauth.AuthenticateContext(context.Background())
+}
+
+// AuthenticateContext obtains a new access token, from the token source when
+// one was given and with the credentials of oauth otherwise.
+func (oauth *forceOauth) AuthenticateContext(ctx context.Context) error {
+	oauth.authMu.Lock()
+	defer oauth.authMu.Unlock()
+
+	return oauth.authenticate(ctx)
+}
+
+// reauthenticate obtains a new access token after force.com rejected
+// expiredToken. When several requests see the same token expire at once, only
+// the first one obtains a new token; the others wait for it and reuse it.
+func (oauth *forceOauth) reauthenticate(ctx context.Context, expiredToken string) error {
+	oauth.authMu.Lock()
+	defer oauth.authMu.Unlock()
+
+	if oauth.token().AccessToken != expiredToken {
+		return nil
+	}
+
+	return oauth.authenticate(ctx)
+}
+
+func (oauth *forceOauth) authenticate(ctx context.Context) error {
+	if oauth.source == nil {
+		if err := oauth.grant(ctx); err != nil {
+			return err
+		}
+	} else {
+		token, err := oauth.source.Refresh(ctx)
+		if err != nil {
+			return err
+		}
+		if err := oauth.setToken(token); err != nil {
+			return err
+		}
+	}
+
+	oauth.tokenRefreshed()
+	return nil
+}
+
+// tokenRefreshed calls onTokenRefreshed, if set, with the current token.
+func (oauth *forceOauth) tokenRefreshed() {
+	if oauth.onTokenRefreshed != nil {
+		oauth.onTokenRefreshed(oauth.token())
+	}
+}
+
+// grant obtains a new access token. It uses the JWT bearer flow when a
+// signing key was given, the client credentials flow when asked to, the
+// refresh token flow when a refresh token but no username was given, and the
+// username-password flow otherwise.
+func (oauth *forceOauth) grant(ctx context.Context) error {
+	if oauth.jwtSigner != nil {
+		return oauth.authenticateJwt(ctx)
+	}
+	if oauth.clientCredentials {
+		return oauth.authenticateClientCredentials(ctx)
+	}
+	if oauth.token().RefreshToken != "" && oauth.userName == "" {
+		return oauth.refresh(ctx)
+	}
+
 	payload := url.Values{
 		"grant_type":    {grantType},
 		"client_id":     {oauth.clientId},
@@ -61,11 +168,61 @@ func (oauth *forceOauth) Authenticate() error {
 		"password":      {fmt.Sprintf("%v%v", oauth.password, oauth.securityToken)},
 	}
 
-	// Build Uri
-	uri := loginUri
+	return oauth.requestToken(ctx, payload)
+}
+
+// refresh obtains a new access token with the refresh token flow.
+func (oauth *forceOauth) refresh(ctx context.Context) error {
+	payload := url.Values{
+		"grant_type":    {refreshTokenGrantType},
+		"client_id":     {oauth.clientId},
+		"refresh_token": {oauth.token().RefreshToken},
+	}
+	if oauth.clientSecret != "" {
+		payload.Set("client_secret", oauth.clientSecret)
+	}
+
+	return oauth.requestToken(ctx, payload)
+}
+
+// authenticateClientCredentials obtains an access token for the integration
+// user of the connected app with the client credentials flow. force.com only
+// supports it on My Domain urls.
+func (oauth *forceOauth) authenticateClientCredentials(ctx context.Context) error {
+	if oauth.loginUrl == "" {
+		return fmt.Errorf("Unable to use the client credentials flow without a My Domain login url")
+	}
+
+	payload := url.Values{
+		"grant_type":    {clientCredentialsGrantType},
+		"client_id":     {oauth.clientId},
+		"client_secret": {oauth.clientSecret},
+	}
+
+	return oauth.requestToken(ctx, payload)
+}
+
+// loginBaseUrl returns the base url of the OAuth endpoints.
+func (oauth *forceOauth) loginBaseUrl() string {
+	if oauth.loginUrl != "" {
+		return strings.TrimRight(oauth.loginUrl, "/")
+	}
 	if oauth.environment == "sandbox" {
-		uri = testLoginUri
+		return testLoginBaseUri
 	}
+	return loginBaseUri
+}
+
+// tokenUrl returns the url OAuth tokens are requested from.
+func (oauth *forceOauth) tokenUrl() string {
+	return oauth.loginBaseUrl() + tokenPath
+}
+
+// postForm posts payload to the OAuth endpoint at path and returns the
+// response body.
+func (oauth *forceOauth) postForm(ctx context.Context, path string, payload url.Values) ([]byte, error) {
+	// Build Uri
+	uri := oauth.loginBaseUrl() + path
 
 	// Build Body
 	body := strings.NewReader(payload.Encode())
@@ -73,8 +230,9 @@ func (oauth *forceOauth) Authenticate() error {
 	// Build Request
 	req, err := http.NewRequest("POST", uri, body)
 	if err != nil {
-		return fmt.Errorf("Error creating authentication request: %v", err)
+		return nil, fmt.Errorf("Error creating authentication request: %v", err)
 	}
+	req = req.WithContext(ctx)
 
 	// Add Headers
 	req.Header.Set("User-Agent", userAgent)
@@ -83,13 +241,13 @@ func (oauth *forceOauth) Authenticate() error {
 
 	resp, err := oauth.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("Error sending authentication request: %v", err)
+		return nil, fmt.Errorf("Error sending authentication request: %v", err)
 	}
 	defer resp.Body.Close()
 
 	respBytes, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("Error reading authentication response bytes: %v", err)
+		return nil, fmt.Errorf("Error reading authentication response bytes: %v", err)
 	}
 
 	// Attempt to parse response as a force.com api error
@@ -97,13 +255,111 @@ func (oauth *forceOauth) Authenticate() error {
 	if err := json.Unmarshal(respBytes, apiError); err == nil {
 		// Check if api error is valid
 		if apiError.Validate() {
-			return apiError
+			return nil, apiError
 		}
 	}
 
-	if err := json.Unmarshal(respBytes, oauth); err != nil {
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("Unexpected status code %v for authentication request: %s", resp.StatusCode, respBytes)
+	}
+
+	return respBytes, nil
+}
+
+// requestToken posts payload to the token url and stores the resulting
+// access token.
+func (oauth *forceOauth) requestToken(ctx context.Context, payload url.Values) error {
+	respBytes, err := oauth.postForm(ctx, tokenPath, payload)
+	if err != nil {
+		return err
+	}
+
+	token := &Token{}
+	if err := json.Unmarshal(respBytes, token); err != nil {
 		return fmt.Errorf("Unable to unmarshal authentication response: %v", err)
 	}
 
+	return oauth.setToken(token)
+}
+
+// validateInstanceUrl checks that the instance url returned along with an
+// access token is the https url of a host, so that the token is never sent
+// in the clear or to a path chosen by the response, and returns it without
+// its trailing slash. Plain http is only accepted for loopback hosts, as
+// used by test servers.
+func validateInstanceUrl(instanceUrl string) (string, error) {
+	if err := validateBaseUrl(instanceUrl); err != nil {
+		return "", fmt.Errorf("Invalid instance url %q: %v", instanceUrl, err)
+	}
+
+	return strings.TrimRight(instanceUrl, "/"), nil
+}
+
+// validateBaseUrl checks that rawUrl is a url of a host with no path, as
+// used for login and instance urls.
+func validateBaseUrl(rawUrl string) error {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case u.Host == "":
+		return fmt.Errorf("missing host")
+	case u.Scheme != "https" && !(u.Scheme == "http" && isLoopback(u.Hostname())):
+		return fmt.Errorf("scheme must be https")
+	case u.User != nil || u.RawQuery != "" || u.Fragment != "":
+		return fmt.Errorf("unexpected user info, query or fragment")
+	case strings.Trim(u.Path, "/") != "":
+		return fmt.Errorf("unexpected path %q", u.Path)
+	}
+
+	return nil
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// token returns the current access token.
+func (oauth *forceOauth) token() *Token {
+	oauth.mu.RLock()
+	defer oauth.mu.RUnlock()
+
+	return &Token{
+		AccessToken:  oauth.AccessToken,
+		RefreshToken: oauth.refreshToken,
+		InstanceUrl:  oauth.InstanceUrl,
+		Id:           oauth.Id,
+		IssuedAt:     oauth.IssuedAt,
+		Signature:    oauth.Signature,
+	}
+}
+
+// setToken replaces the current access token. The refresh token is only
+// replaced when a new one was issued. Tokens are refused unless their instance
+// url passes validateInstanceUrl, wherever they came from.
+func (oauth *forceOauth) setToken(token *Token) error {
+	instanceUrl, err := validateInstanceUrl(token.InstanceUrl)
+	if err != nil {
+		return err
+	}
+
+	oauth.mu.Lock()
+	defer oauth.mu.Unlock()
+
+	oauth.AccessToken = token.AccessToken
+	oauth.InstanceUrl = instanceUrl
+	oauth.Id = token.Id
+	oauth.IssuedAt = token.IssuedAt
+	oauth.Signature = token.Signature
+	if token.RefreshToken != "" {
+		oauth.refreshToken = token.RefreshToken
+	}
+
 	return nil
 }
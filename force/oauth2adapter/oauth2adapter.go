@@ -0,0 +1,59 @@
+// Package oauth2adapter lets a ForceApi obtain its tokens from a
+// golang.org/x/oauth2 TokenSource. It lives apart from package force so that
+// only its users depend on golang.org/x/oauth2.
+//
+//	forceApi, err := force.New(
+//		force.WithTokenSource(oauth2adapter.TokenSource(config.TokenSource(ctx, token), "")),
+//	)
+package oauth2adapter
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/oauth2"
+
+	"github.com/opendoor-labs/go-force/force"
+)
+
+// TokenSource adapts a golang.org/x/oauth2 TokenSource, such as one from
+// oauth2.Config.TokenSource, for use with force.WithTokenSource. force.com
+// returns the instance url along with the access token, which is used when
+// the oauth2 token carries it; instanceUrl is used otherwise.
+//
+// Refresh asks src for a token again. Sources that cache tokens until they
+// expire, like oauth2.ReuseTokenSource, may hand back a token force.com
+// already rejected; the request is then failed rather than retried.
+func TokenSource(src oauth2.TokenSource, instanceUrl string) force.TokenSource {
+	return &tokenSource{src: src, instanceUrl: instanceUrl}
+}
+
+type tokenSource struct {
+	src         oauth2.TokenSource
+	instanceUrl string
+}
+
+func (s *tokenSource) Token(ctx context.Context) (*force.Token, error) {
+	t, err := s.src.Token()
+	if err != nil {
+		return nil, fmt.Errorf("Unable to obtain oauth2 token: %v", err)
+	}
+
+	token := &force.Token{
+		AccessToken:  t.AccessToken,
+		RefreshToken: t.RefreshToken,
+		InstanceUrl:  s.instanceUrl,
+	}
+	if instanceUrl, ok := t.Extra("instance_url").(string); ok && instanceUrl != "" {
+		token.InstanceUrl = instanceUrl
+	}
+	if id, ok := t.Extra("id").(string); ok {
+		token.Id = id
+	}
+
+	return token, nil
+}
+
+func (s *tokenSource) Refresh(ctx context.Context) (*force.Token, error) {
+	return s.Token(ctx)
+}
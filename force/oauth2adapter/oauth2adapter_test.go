@@ -0,0 +1,34 @@
+package oauth2adapter_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/oauth2"
+
+	"github.com/opendoor-labs/go-force/force/oauth2adapter"
+)
+
+func TestOauth2adapter(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Oauth2adapter Suite")
+}
+
+var _ = Describe("TokenSource", func() {
+	It("should adapt an oauth2 token source", func() {
+		t := (&oauth2.Token{AccessToken: "at"}).WithExtra(map[string]interface{}{
+			"instance_url": "https://example.my.salesforce.com",
+		})
+
+		token, err := oauth2adapter.TokenSource(oauth2.StaticTokenSource(t), "https://fallback").Token(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.AccessToken).To(Equal("at"))
+		Expect(token.InstanceUrl).To(Equal("https://example.my.salesforce.com"))
+
+		token, err = oauth2adapter.TokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "at"}), "https://fallback").Token(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.InstanceUrl).To(Equal("https://fallback"))
+	})
+})
@@ -2,6 +2,7 @@ package force
 
 import (
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
 )
 
 var _ = Describe("Testing with Ginkgo", func() {
@@ -14,3 +15,20 @@ var _ = Describe("Testing with Ginkgo", func() {
 		}
 	})
 })
+
+var _ = Describe("forceOauth", func() {
+	It("should name the missing field without revealing credentials", func() {
+		oauth := &forceOauth{
+			AccessToken:  "secret-token",
+			clientSecret: "secret",
+			password:     "hunter2",
+		}
+
+		err := oauth.Validate()
+		Expect(err).To(MatchError("Invalid Force Oauth Object: missing instance url"))
+
+		oauth.InstanceUrl = "https://example.my.salesforce.com"
+		oauth.AccessToken = ""
+		Expect(oauth.Validate()).To(MatchError("Invalid Force Oauth Object: missing access token"))
+	})
+})
@@ -0,0 +1,324 @@
+package force
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// DefaultApiVersion is the force.com api version used by New unless
+// WithVersion is given.
+const DefaultApiVersion = "v52.0"
+
+// An Option configures the ForceApi built by New.
+type Option func(*options)
+
+type options struct {
+	version          string
+	httpClient       HttpClient
+	clientId         string
+	clientSecret     string
+	tokenSource      func(o *options) TokenSource
+	tokenStore       TokenStore
+	onTokenRefreshed func(token *Token)
+	loginUrl         string
+	environment      string
+	logger           ForceApiLogger
+	logPrefix        string
+	lazyMetadata     bool
+	retryPolicy      *RetryPolicy
+	defaultHeader    http.Header
+	requestErrors    bool
+	err              error
+}
+
+// New builds a ForceApi from the given options. Exactly one auth option,
+// such as WithPassword or WithAccessToken, is required. Unless
+// WithLazyMetadata is given, New authenticates and loads the api resources
+// and the list of sobjects before returning.
+//
+//	forceApi, err := force.New(
+//		force.WithPassword(clientId, clientSecret, userName, password, securityToken),
+//		force.WithRetryPolicy(force.DefaultRetryPolicy()),
+//	)
+func New(opts ...Option) (*ForceApi, error) {
+	return NewContext(context.Background(), opts...)
+}
+
+// NewContext is like New but carries ctx to the underlying http requests.
+func NewContext(ctx context.Context, opts ...Option) (*ForceApi, error) {
+	o := &options{
+		version:    DefaultApiVersion,
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.err != nil {
+		return nil, o.err
+	}
+	if o.tokenSource == nil {
+		return nil, fmt.Errorf("Unable to create ForceApi: no auth option given")
+	}
+
+	source := o.tokenSource(o)
+	if o.tokenStore != nil {
+		source = &storedTokenSource{source: source, store: o.tokenStore}
+	}
+
+	oauth := &forceOauth{
+		clientId:         o.clientId,
+		clientSecret:     o.clientSecret,
+		httpClient:       o.httpClient,
+		loginUrl:         o.loginUrl,
+		environment:      o.environment,
+		source:           source,
+		onTokenRefreshed: o.onTokenRefreshed,
+	}
+
+	forceApi := &ForceApi{
+		apiResources:           make(map[string]string),
+		apiSObjects:            make(map[string]*SObjectMetaData),
+		apiSObjectDescriptions: make(map[string]*SObjectDescription),
+		apiVersion:             o.version,
+		oauth:                  oauth,
+		httpClient:             o.httpClient,
+		retryPolicy:            o.retryPolicy,
+		defaultHeader:          o.defaultHeader,
+		requestErrors:          o.requestErrors,
+	}
+	if o.logger != nil {
+		forceApi.TraceOn(o.logPrefix, o.logger)
+	}
+
+	token, err := oauth.source.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := oauth.setToken(token); err != nil {
+		return nil, err
+	}
+
+	// We need to check for oath correctness here, since the token may not have been generated by us.
+	if err := oauth.Validate(); err != nil {
+		return nil, err
+	}
+	oauth.tokenRefreshed()
+
+	if o.lazyMetadata {
+		return forceApi, nil
+	}
+
+	if err := forceApi.ResetResourcesContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return forceApi, nil
+}
+
+// WithTokenSource obtains access tokens from ts, asking it for a new one
+// whenever the session expires.
+func WithTokenSource(ts TokenSource) Option {
+	return func(o *options) {
+		o.tokenSource = func(o *options) TokenSource {
+			return ts
+		}
+	}
+}
+
+// WithPassword authenticates with the OAuth username-password flow. The
+// session is renewed the same way when it expires.
+func WithPassword(clientId, clientSecret, userName, password, securityToken string) Option {
+	return func(o *options) {
+		o.clientId = clientId
+		o.clientSecret = clientSecret
+		o.tokenSource = func(o *options) TokenSource {
+			return &PasswordTokenSource{
+				ClientId:      clientId,
+				ClientSecret:  clientSecret,
+				UserName:      userName,
+				Password:      password,
+				SecurityToken: securityToken,
+				LoginUrl:      o.defaultLoginUrl(),
+				HttpClient:    o.httpClient,
+			}
+		}
+	}
+}
+
+// WithAccessToken uses an access token obtained elsewhere. The session can't
+// be renewed once it expires.
+func WithAccessToken(clientId, accessToken, instanceUrl string) Option {
+	return func(o *options) {
+		o.clientId = clientId
+		o.tokenSource = func(o *options) TokenSource {
+			return StaticTokenSource(&Token{
+				AccessToken: accessToken,
+				InstanceUrl: instanceUrl,
+			})
+		}
+	}
+}
+
+// WithRefreshToken obtains an access token with the OAuth refresh token flow,
+// and renews it the same way when it expires. clientSecret may be empty for
+// connected apps that don't require it.
+func WithRefreshToken(clientId, clientSecret, refreshToken string) Option {
+	return func(o *options) {
+		o.clientId = clientId
+		o.clientSecret = clientSecret
+		o.tokenSource = func(o *options) TokenSource {
+			return &RefreshTokenSource{
+				ClientId:     clientId,
+				ClientSecret: clientSecret,
+				RefreshToken: refreshToken,
+				LoginUrl:     o.defaultLoginUrl(),
+				HttpClient:   o.httpClient,
+			}
+		}
+	}
+}
+
+// WithClientCredentials authenticates as the integration user of a connected
+// app with the OAuth client credentials flow, and renews the session the same
+// way when it expires. force.com only supports the flow on My Domain urls,
+// such as "https://example.my.salesforce.com", which myDomainUrl sets like
+// WithLoginUrl.
+func WithClientCredentials(clientId, clientSecret, myDomainUrl string) Option {
+	return func(o *options) {
+		if err := validateBaseUrl(myDomainUrl); err != nil {
+			o.err = fmt.Errorf("Invalid My Domain url %q: %v", myDomainUrl, err)
+			return
+		}
+		o.clientId = clientId
+		o.clientSecret = clientSecret
+		o.loginUrl = myDomainUrl
+		o.tokenSource = func(o *options) TokenSource {
+			return &ClientCredentialsTokenSource{
+				ClientId:     clientId,
+				ClientSecret: clientSecret,
+				LoginUrl:     o.loginUrl,
+				HttpClient:   o.httpClient,
+			}
+		}
+	}
+}
+
+// WithTokenStore loads the access token from store instead of obtaining a
+// new one when one was stored, and saves every new access token to it. When
+// the stored token expires and another process sharing store already
+// replaced it, its token is used instead of obtaining yet another one.
+func WithTokenStore(store TokenStore) Option {
+	return func(o *options) {
+		o.tokenStore = store
+	}
+}
+
+// WithOnTokenRefreshed calls fn with every access token the ForceApi starts
+// using: the one obtained in New, and each one replacing an expired token.
+// fn is called while other requests wait for the new token, so it should
+// return quickly.
+func WithOnTokenRefreshed(fn func(token *Token)) Option {
+	return func(o *options) {
+		o.onTokenRefreshed = fn
+	}
+}
+
+// WithVersion sets the force.com api version, e.g. "v52.0". It defaults to
+// DefaultApiVersion.
+func WithVersion(version string) Option {
+	return func(o *options) {
+		o.version = version
+	}
+}
+
+// WithHttpClient sets the client used for every request. It defaults to
+// http.DefaultClient.
+func WithHttpClient(httpClient HttpClient) Option {
+	return func(o *options) {
+		if httpClient != nil {
+			o.httpClient = httpClient
+		}
+	}
+}
+
+// WithLogger turns tracing on from the start, like TraceOn.
+func WithLogger(prefix string, logger ForceApiLogger) Option {
+	return func(o *options) {
+		o.logPrefix = prefix
+		o.logger = logger
+	}
+}
+
+// WithLoginUrl sets the base url of the OAuth endpoints, used to obtain,
+// refresh and revoke tokens. Orgs enforcing My Domain login use their My
+// Domain url, like "https://example.my.salesforce.com"; scratch orgs and
+// government cloud orgs have their own login urls too. It defaults to
+// https://login.salesforce.com, or https://test.salesforce.com for the
+// sandbox environment.
+func WithLoginUrl(loginUrl string) Option {
+	return func(o *options) {
+		if err := validateBaseUrl(loginUrl); err != nil {
+			o.err = fmt.Errorf("Invalid login url %q: %v", loginUrl, err)
+			return
+		}
+		o.loginUrl = loginUrl
+	}
+}
+
+// WithEnvironment picks the default login url: "sandbox" uses
+// https://test.salesforce.com, anything else https://login.salesforce.com.
+func WithEnvironment(environment string) Option {
+	return func(o *options) {
+		o.environment = environment
+	}
+}
+
+// WithLazyMetadata skips loading the api resources and the list of sobjects
+// in New. Resource urls are derived from the api version instead, and the
+// list of sobjects is only loaded by the methods that need it, such as
+// DescribeSObjects and HasAccess.
+func WithLazyMetadata() Option {
+	return func(o *options) {
+		o.lazyMetadata = true
+	}
+}
+
+// WithRetryPolicy retries requests that failed with a transient error, like
+// SetRetryPolicy.
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(o *options) {
+		o.retryPolicy = policy
+	}
+}
+
+// WithHeader adds a header sent with every api request. Headers set by
+// ForceApi itself, such as Authorization, can't be overridden.
+func WithHeader(key, value string) Option {
+	return func(o *options) {
+		if o.defaultHeader == nil {
+			o.defaultHeader = http.Header{}
+		}
+		o.defaultHeader.Add(key, value)
+	}
+}
+
+// WithRequestErrors makes requests force.com answered with force.com api
+// errors return a *RequestError, which carries the status code and the rest
+// of the response along with the ApiErrors, instead of the ApiErrors
+// themselves. Code asserting err.(force.ApiErrors) has to use errors.As
+// before turning it on.
+func WithRequestErrors() Option {
+	return func(o *options) {
+		o.requestErrors = true
+	}
+}
+
+// defaultLoginUrl returns the login url given to the built-in token sources.
+func (o *options) defaultLoginUrl() string {
+	if o.loginUrl == "" && o.environment == "sandbox" {
+		return testLoginBaseUri
+	}
+	return o.loginUrl
+}
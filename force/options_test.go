@@ -0,0 +1,225 @@
+package force_test
+
+import (
+	"io/ioutil"
+	"net/url"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/forcefakes"
+)
+
+var _ = Describe("New", func() {
+	var httpClient forcefakes.FakeHttpClient
+
+	BeforeEach(func() {
+		httpClient = forcefakes.FakeHttpClient{}
+	})
+
+	It("should require an auth option", func() {
+		_, err := force.New(force.WithHttpClient(&httpClient))
+		Expect(err).To(HaveOccurred())
+		Expect(httpClient.DoCallCount()).To(Equal(0))
+	})
+
+	It("should authenticate and load metadata eagerly", func() {
+		httpClient.DoReturnsOnCall(0, NewFakeResponse(FakeOauthRespBody, 200), nil)
+		httpClient.DoReturnsOnCall(1, NewFakeResponse(`{"sobjects": "/services/data/v40.0/sobjects"}`, 200), nil)
+		httpClient.DoReturnsOnCall(2, NewFakeResponse(`{"sobjects": [{"name": "APIName", "urls": {"sobject": "the/url"}}]}`, 200), nil)
+
+		forceApi, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithVersion("v40.0"),
+			force.WithPassword("id", "secret", "user", "pass", "token"),
+			force.WithLoginUrl("https://example.my.salesforce.com/"),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(forceApi.HasAccess([]string{"APIName"})).To(BeTrue())
+		Expect(httpClient.DoCallCount()).To(Equal(3))
+
+		req := httpClient.DoArgsForCall(0)
+		Expect(req.URL.String()).To(Equal("https://example.my.salesforce.com/services/oauth2/token"))
+		body, _ := ioutil.ReadAll(req.Body)
+		form, _ := url.ParseQuery(string(body))
+		Expect(form.Get("grant_type")).To(Equal("password"))
+		Expect(form.Get("password")).To(Equal("passtoken"))
+
+		Expect(httpClient.DoArgsForCall(1).URL.Path).To(HaveSuffix("/services/data/v40.0"))
+	})
+
+	It("should skip loading metadata when lazy", func() {
+		httpClient.DoReturnsOnCall(0, NewFakeResponse(`{"records": []}`, 200), nil)
+
+		forceApi, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithAccessToken("id", "token", "https://example.my.salesforce.com"),
+			force.WithLazyMetadata(),
+			force.WithHeader("Sforce-Call-Options", "client=go-force"),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(httpClient.DoCallCount()).To(Equal(0))
+
+		Expect(forceApi.Query("SELECT Id FROM Account", &map[string]interface{}{})).To(Succeed())
+		req := httpClient.DoArgsForCall(0)
+		Expect(req.URL.Path).To(Equal("/services/data/" + force.DefaultApiVersion + "/query"))
+		Expect(req.Header.Get("Sforce-Call-Options")).To(Equal("client=go-force"))
+		Expect(req.Header.Get("Authorization")).To(Equal("Bearer token"))
+	})
+
+	It("should derive sobject urls when lazy", func() {
+		httpClient.DoReturnsOnCall(0, NewFakeResponse(`{"Id": "001A"}`, 200), nil)
+
+		forceApi, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithVersion("v40.0"),
+			force.WithAccessToken("id", "token", "https://example.my.salesforce.com"),
+			force.WithLazyMetadata(),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(forceApi.GetSObject("001A", nil, &CompositeSObject{})).To(Succeed())
+		Expect(httpClient.DoArgsForCall(0).URL.Path).To(Equal("/services/data/v40.0/sobjects/APIName/001A"))
+	})
+
+	It("should obtain an access token with a refresh token", func() {
+		httpClient.DoReturnsOnCall(0, NewFakeResponse(FakeOauthRespBody, 200), nil)
+
+		forceApi, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithRefreshToken("id", "", "refresh"),
+			force.WithEnvironment("sandbox"),
+			force.WithLazyMetadata(),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(forceApi.GetAccessToken()).NotTo(BeEmpty())
+
+		req := httpClient.DoArgsForCall(0)
+		Expect(req.URL.Host).To(Equal("test.salesforce.com"))
+		body, _ := ioutil.ReadAll(req.Body)
+		form, _ := url.ParseQuery(string(body))
+		Expect(form.Get("grant_type")).To(Equal("refresh_token"))
+		Expect(form.Get("refresh_token")).To(Equal("refresh"))
+		Expect(form).NotTo(HaveKey("client_secret"))
+	})
+
+	It("should authenticate with client credentials against a My Domain", func() {
+		httpClient.DoReturnsOnCall(0, NewFakeResponse(FakeOauthRespBody, 200), nil)
+		httpClient.DoReturnsOnCall(1, NewFakeResponse(`[{"errorCode": "INVALID_SESSION_ID", "message": "Session expired or invalid"}]`, 401), nil)
+		httpClient.DoReturnsOnCall(2, NewFakeResponse(`{"access_token": "renewed", "instance_url": "https://iu.my.salesforce.com"}`, 200), nil)
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`{"records": []}`, 200), nil)
+
+		forceApi, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithClientCredentials("id", "secret", "https://example.my.salesforce.com"),
+			force.WithLazyMetadata(),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		req := httpClient.DoArgsForCall(0)
+		Expect(req.URL.String()).To(Equal("https://example.my.salesforce.com/services/oauth2/token"))
+		body, _ := ioutil.ReadAll(req.Body)
+		form, _ := url.ParseQuery(string(body))
+		Expect(form).To(Equal(url.Values{
+			"grant_type":    {"client_credentials"},
+			"client_id":     {"id"},
+			"client_secret": {"secret"},
+		}))
+
+		Expect(forceApi.Query("SELECT Id FROM Account", &map[string]interface{}{})).To(Succeed())
+		Expect(httpClient.DoArgsForCall(2).URL.String()).To(Equal("https://example.my.salesforce.com/services/oauth2/token"))
+		Expect(httpClient.DoArgsForCall(3).Header.Get("Authorization")).To(Equal("Bearer renewed"))
+	})
+
+	It("should require a My Domain for client credentials", func() {
+		_, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithClientCredentials("id", "secret", ""),
+		)
+		Expect(err).To(HaveOccurred())
+		Expect(httpClient.DoCallCount()).To(Equal(0))
+	})
+
+	It("should reject an invalid My Domain url", func() {
+		_, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithClientCredentials("id", "secret", "http://example.my.salesforce.com"),
+		)
+		Expect(err).To(MatchError(ContainSubstring("Invalid My Domain url")))
+		Expect(httpClient.DoCallCount()).To(Equal(0))
+	})
+
+	It("should apply the retry policy", func() {
+		httpClient.DoReturnsOnCall(0, NewFakeResponse(`oops`, 503), nil)
+		httpClient.DoReturnsOnCall(1, NewFakeResponse(`{"records": []}`, 200), nil)
+
+		forceApi, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithAccessToken("id", "token", "https://example.my.salesforce.com"),
+			force.WithLazyMetadata(),
+			force.WithRetryPolicy(&force.RetryPolicy{InitialBackoff: time.Millisecond}),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(forceApi.Query("SELECT Id FROM Account", &map[string]interface{}{})).To(Succeed())
+		Expect(httpClient.DoCallCount()).To(Equal(2))
+	})
+
+	It("should reject an invalid login url", func() {
+		for _, loginUrl := range []string{"example.my.salesforce.com", "http://example.my.salesforce.com", "https://example.my.salesforce.com/services"} {
+			_, err := force.New(
+				force.WithHttpClient(&httpClient),
+				force.WithPassword("id", "secret", "user", "pass", "token"),
+				force.WithLoginUrl(loginUrl),
+			)
+			Expect(err).To(HaveOccurred(), loginUrl)
+		}
+		Expect(httpClient.DoCallCount()).To(Equal(0))
+	})
+
+	It("should reject an insecure instance url", func() {
+		httpClient.DoReturnsOnCall(0, NewFakeResponse(`{"access_token": "at", "instance_url": "http://attacker.example.com"}`, 200), nil)
+
+		_, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithPassword("id", "secret", "user", "pass", "token"),
+		)
+		Expect(err).To(MatchError(ContainSubstring("Invalid instance url")))
+		Expect(httpClient.DoCallCount()).To(Equal(1))
+	})
+
+	It("should refresh an access token against the login url", func() {
+		httpClient.DoReturnsOnCall(0, NewFakeResponse(`{"access_token": "renewed", "instance_url": "https://example.my.salesforce.com/"}`, 200), nil)
+
+		forceApi, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithAccessToken("id", "token", "https://example.my.salesforce.com"),
+			force.WithLoginUrl("https://example.my.salesforce.com"),
+			force.WithLazyMetadata(),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(forceApi.RefreshToken()).To(Succeed())
+		Expect(forceApi.GetAccessToken()).To(Equal("renewed"))
+		Expect(forceApi.GetInstanceURL()).To(Equal("https://example.my.salesforce.com"))
+
+		req := httpClient.DoArgsForCall(0)
+		Expect(req.URL.String()).To(Equal("https://example.my.salesforce.com/services/oauth2/token"))
+		body, _ := ioutil.ReadAll(req.Body)
+		form, _ := url.ParseQuery(string(body))
+		Expect(form.Get("grant_type")).To(Equal("refresh_token"))
+		Expect(form.Get("client_id")).To(Equal("id"))
+	})
+	It("should use the access token given to CreateWithRefreshToken", func() {
+		httpClient.DoReturnsOnCall(0, NewFakeResponse(`{"sobjects": "/services/data/v40.0/sobjects"}`, 200), nil)
+		httpClient.DoReturnsOnCall(1, NewFakeResponse(`{"sobjects": []}`, 200), nil)
+
+		forceApi, err := force.CreateWithRefreshToken("v40.0", "id", "token", "https://example.my.salesforce.com", &httpClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(forceApi.GetAccessToken()).To(Equal("token"))
+		Expect(httpClient.DoCallCount()).To(Equal(2))
+		Expect(httpClient.DoArgsForCall(0).URL.Path).To(Equal("/services/data/v40.0"))
+	})
+})
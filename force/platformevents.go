@@ -0,0 +1,88 @@
+package force
+
+import (
+	"context"
+)
+
+// operationEnqueued is the status code force.com reports, along with the
+// EventUuid, for events configured to publish immediately.
+const operationEnqueued = "OPERATION_ENQUEUED"
+
+// PublishResult is the outcome of publishing a platform event.
+//
+// Platform events are either configured to publish immediately or to publish
+// after commit. Events published immediately are enqueued and published
+// asynchronously, even when the rest of the request fails or is rolled back:
+// Enqueued is set and EventUuid identifies the event, which is also the
+// EventUuid subscribers receive. A failure to deliver them afterwards isn't
+// reported here. Events published after commit are published only once the
+// transaction of the request commits, and are rolled back along with it;
+// force.com doesn't report their EventUuid.
+//
+// Errors holds the reasons an event wasn't published; in either case, an
+// event with errors was never published.
+type PublishResult struct {
+	Id        string
+	Success   bool
+	Enqueued  bool
+	EventUuid string
+	Errors    ApiErrors
+}
+
+// PublishEvent publishes a single platform event, such as a struct whose
+// APIName is "Order_Placed__e". Events that fail to publish return an error
+// holding the reasons, as with InsertSObject.
+func (forceApi *ForceApi) PublishEvent(in SObject) (*PublishResult, error) {
+	return forceApi.PublishEventContext(context.Background(), in)
+}
+
+// PublishEventContext is like PublishEvent but carries ctx to the underlying http request.
+func (forceApi *ForceApi) PublishEventContext(ctx context.Context, in SObject) (*PublishResult, error) {
+	resp, err := forceApi.InsertSObjectContext(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPublishResult(resp), nil
+}
+
+// PublishEvents publishes platform events, up to 200 per request, using the
+// sObject Collections resource. The returned results are aligned with in;
+// check their Success and Errors for the outcome of each event. See
+// InsertSObjects for how allOrNone works, keeping in mind that it doesn't
+// apply to events published immediately, which are published regardless.
+func (forceApi *ForceApi) PublishEvents(allOrNone bool, in []SObject) ([]*PublishResult, error) {
+	return forceApi.PublishEventsContext(context.Background(), allOrNone, in)
+}
+
+// PublishEventsContext is like PublishEvents but carries ctx to the underlying http requests.
+func (forceApi *ForceApi) PublishEventsContext(ctx context.Context, allOrNone bool, in []SObject) ([]*PublishResult, error) {
+	resps, err := forceApi.InsertSObjectsContext(ctx, allOrNone, in)
+
+	results := make([]*PublishResult, len(resps))
+	for i, resp := range resps {
+		results[i] = newPublishResult(resp)
+	}
+
+	return results, err
+}
+
+// newPublishResult extracts the EventUuid force.com reports as an error of
+// events published immediately.
+func newPublishResult(resp *SObjectResponse) *PublishResult {
+	result := &PublishResult{
+		Id:      resp.Id,
+		Success: resp.Success,
+	}
+
+	for _, err := range resp.Errors {
+		if err.StatusCode == operationEnqueued || err.ErrorCode == operationEnqueued {
+			result.Enqueued = true
+			result.EventUuid = err.Message
+			continue
+		}
+		result.Errors = append(result.Errors, err)
+	}
+
+	return result
+}
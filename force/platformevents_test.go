@@ -0,0 +1,85 @@
+package force_test
+
+import (
+	"io/ioutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/forcefakes"
+)
+
+var _ = Describe("PlatformEvents", func() {
+	var httpClient forcefakes.FakeHttpClient
+	var forceApi *force.ForceApi
+
+	BeforeEach(func() {
+		httpClient = forcefakes.FakeHttpClient{}
+
+		var err error
+		forceApi, err = createForceApi(&httpClient)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should publish an event immediately", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`{"id": "e00A", "success": true, "errors": [
+			{"statusCode": "OPERATION_ENQUEUED", "message": "08fa9a2d-4f4e-4b6c-a6a0-5c1d7c3e3a1b", "fields": []}
+		]}`, 201), nil)
+
+		result, err := forceApi.PublishEvent(&CompositeSObject{Name: "Order"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(&force.PublishResult{
+			Id:        "e00A",
+			Success:   true,
+			Enqueued:  true,
+			EventUuid: "08fa9a2d-4f4e-4b6c-a6a0-5c1d7c3e3a1b",
+		}))
+
+		req := httpClient.DoArgsForCall(3)
+		Expect(req.Method).To(Equal("POST"))
+		Expect(req.URL.Path).To(Equal("/url"))
+		body, _ := ioutil.ReadAll(req.Body)
+		Expect(body).To(MatchJSON(`{"Name": "Order"}`))
+	})
+
+	It("should publish an event after commit", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`{"id": "e00A", "success": true, "errors": []}`, 201), nil)
+
+		result, err := forceApi.PublishEvent(&CompositeSObject{Name: "Order"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(&force.PublishResult{Id: "e00A", Success: true}))
+	})
+
+	It("should return publish errors", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`[{"errorCode": "LIMIT_EXCEEDED", "message": "You have exceeded the hourly event publishing limit", "fields": []}]`, 400), nil)
+
+		_, err := forceApi.PublishEvent(&CompositeSObject{Name: "Order"})
+		Expect(err).To(MatchError(ContainSubstring("LIMIT_EXCEEDED")))
+	})
+
+	It("should publish batches of events", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`[
+			{"id": "e00A", "success": true, "errors": [{"statusCode": "OPERATION_ENQUEUED", "message": "uuid-1", "fields": []}]},
+			{"success": false, "errors": [{"statusCode": "REQUIRED_FIELD_MISSING", "message": "Required fields are missing: [Name]", "fields": ["Name"]}]},
+			{"id": "e00C", "success": true, "errors": []}
+		]`, 200), nil)
+
+		results, err := forceApi.PublishEvents(false, []force.SObject{
+			&CompositeSObject{Name: "Order 1"},
+			&CompositeSObject{},
+			&CompositeSObject{Name: "Order 3"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(HaveLen(3))
+		Expect(results[0]).To(Equal(&force.PublishResult{Id: "e00A", Success: true, Enqueued: true, EventUuid: "uuid-1"}))
+		Expect(results[1].Success).To(BeFalse())
+		Expect(results[1].Enqueued).To(BeFalse())
+		Expect(results[1].Errors).To(HaveLen(1))
+		Expect(results[1].Errors[0].StatusCode).To(Equal("REQUIRED_FIELD_MISSING"))
+		Expect(results[2]).To(Equal(&force.PublishResult{Id: "e00C", Success: true}))
+
+		req := httpClient.DoArgsForCall(3)
+		Expect(req.URL.Path).To(HaveSuffix("/composite/sobjects"))
+	})
+})
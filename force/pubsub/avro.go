@@ -0,0 +1,482 @@
+package pubsub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/linkedin/goavro/v2"
+
+	"github.com/opendoor-labs/go-force/forcejson"
+)
+
+// Schema is the Avro schema of the events of a topic.
+type Schema struct {
+	Id   string
+	Json string
+
+	codec *goavro.Codec
+	// root is the parsed schema, and named holds its named types by full
+	// name, to walk values along with their schema.
+	root  interface{}
+	named map[string]interface{}
+}
+
+// NewSchema parses the Avro schema with the given id, as returned by the
+// Pub/Sub API.
+func NewSchema(id, schemaJson string) (*Schema, error) {
+	codec, err := goavro.NewCodec(schemaJson)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse schema %v: %v", id, err)
+	}
+
+	var root interface{}
+	if err := json.Unmarshal([]byte(schemaJson), &root); err != nil {
+		return nil, fmt.Errorf("Unable to parse schema %v: %v", id, err)
+	}
+
+	schema := &Schema{
+		Id:    id,
+		Json:  schemaJson,
+		codec: codec,
+		root:  root,
+		named: make(map[string]interface{}),
+	}
+	schema.register(root, "")
+
+	return schema, nil
+}
+
+// Decode decodes an event payload into a map of its fields. Unlike the
+// native form of goavro, values of union fields, which force.com uses for
+// every nullable field, are unwrapped: a null field is nil, and any other a
+// plain value, with records as nested maps.
+func (s *Schema) Decode(payload []byte) (map[string]interface{}, error) {
+	native, _, err := s.codec.NativeFromBinary(payload)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to decode event with schema %v: %v", s.Id, err)
+	}
+
+	fields, ok := s.unwrap(s.root, "", native).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unable to decode event with schema %v: not a record", s.Id)
+	}
+
+	return fields, nil
+}
+
+// Unmarshal decodes an event payload into out, a struct with force tags such
+// as the SObject structs used with the rest of the api.
+func (s *Schema) Unmarshal(payload []byte, out interface{}) error {
+	fields, err := s.Decode(payload)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("Unable to unmarshal event with schema %v: %v", s.Id, err)
+	}
+
+	if err := forcejson.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("Unable to unmarshal event with schema %v: %v", s.Id, err)
+	}
+
+	return nil
+}
+
+// Encode encodes in, a map of fields or a struct with force tags, into an
+// event payload. Fields missing from in take their default value, which is
+// null for the nullable fields of force.com schemas.
+func (s *Schema) Encode(in interface{}) ([]byte, error) {
+	return s.encode(in, nil)
+}
+
+// encode is like Encode, using defaults for the fields missing from in
+// rather than the defaults of the schema.
+func (s *Schema) encode(in interface{}, defaults map[string]interface{}) ([]byte, error) {
+	fields, ok := in.(map[string]interface{})
+	if !ok {
+		data, err := forcejson.Marshal(in)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to encode event with schema %v: %v", s.Id, err)
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&fields); err != nil {
+			return nil, fmt.Errorf("Unable to encode event with schema %v: %v", s.Id, err)
+		}
+	}
+
+	if len(defaults) != 0 {
+		withDefaults := make(map[string]interface{}, len(fields)+len(defaults))
+		for name, value := range defaults {
+			withDefaults[name] = value
+		}
+		for name, value := range fields {
+			withDefaults[name] = value
+		}
+		fields = withDefaults
+	}
+
+	native, err := s.wrap(s.root, "", fields)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to encode event with schema %v: %v", s.Id, err)
+	}
+
+	payload, err := s.codec.BinaryFromNative(nil, native)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to encode event with schema %v: %v", s.Id, err)
+	}
+
+	return payload, nil
+}
+
+// hasField reports whether the schema is a record with the given field.
+func (s *Schema) hasField(name string) bool {
+	root, ok := s.root.(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	fields, _ := root["fields"].([]interface{})
+	for _, field := range fields {
+		if field, ok := field.(map[string]interface{}); ok && field["name"] == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// register records the named types defined by schema.
+func (s *Schema) register(schema interface{}, namespace string) {
+	switch schema := schema.(type) {
+	case []interface{}:
+		for _, branch := range schema {
+			s.register(branch, namespace)
+		}
+	case map[string]interface{}:
+		switch schema["type"] {
+		case "record", "error", "enum", "fixed":
+			name := fullName(schema, namespace)
+			s.named[name] = schema
+			if schema["type"] == "enum" || schema["type"] == "fixed" {
+				return
+			}
+			fields, _ := schema["fields"].([]interface{})
+			for _, field := range fields {
+				if field, ok := field.(map[string]interface{}); ok {
+					s.register(field["type"], namespaceOf(name))
+				}
+			}
+		case "array":
+			s.register(schema["items"], namespace)
+		case "map":
+			s.register(schema["values"], namespace)
+		default:
+			s.register(schema["type"], namespace)
+		}
+	}
+}
+
+// resolve returns the definition of the type schema refers to by name, along
+// with the namespace of the types it refers to in turn.
+func (s *Schema) resolve(schema interface{}, namespace string) (interface{}, string) {
+	name, ok := schema.(string)
+	if !ok {
+		if schema, ok := schema.(map[string]interface{}); ok {
+			switch schema["type"] {
+			case "record", "error", "enum", "fixed":
+				return schema, namespaceOf(fullName(schema, namespace))
+			}
+		}
+		return schema, namespace
+	}
+
+	if named, ok := s.named[name]; ok {
+		return named, namespaceOf(name)
+	}
+	if namespace != "" {
+		if named, ok := s.named[namespace+"."+name]; ok {
+			return named, namespace
+		}
+	}
+
+	return schema, namespace
+}
+
+// unwrap converts native, a value decoded by goavro with the given schema,
+// into plain values.
+func (s *Schema) unwrap(schema interface{}, namespace string, native interface{}) interface{} {
+	schema, namespace = s.resolve(schema, namespace)
+
+	switch schema := schema.(type) {
+	case []interface{}:
+		union, ok := native.(map[string]interface{})
+		if !ok || len(union) != 1 {
+			return native
+		}
+		for branchName, value := range union {
+			for _, branch := range schema {
+				if s.branchName(branch, namespace) == branchName {
+					return s.unwrap(branch, namespace, value)
+				}
+			}
+			return value
+		}
+	case map[string]interface{}:
+		switch schema["type"] {
+		case "record", "error":
+			record, ok := native.(map[string]interface{})
+			if !ok {
+				return native
+			}
+			fields, _ := schema["fields"].([]interface{})
+			out := make(map[string]interface{}, len(record))
+			for _, field := range fields {
+				field, _ := field.(map[string]interface{})
+				name, _ := field["name"].(string)
+				if value, ok := record[name]; ok {
+					out[name] = s.unwrap(field["type"], namespace, value)
+				}
+			}
+			return out
+		case "array":
+			items, ok := native.([]interface{})
+			if !ok {
+				return native
+			}
+			out := make([]interface{}, len(items))
+			for i, item := range items {
+				out[i] = s.unwrap(schema["items"], namespace, item)
+			}
+			return out
+		case "map":
+			values, ok := native.(map[string]interface{})
+			if !ok {
+				return native
+			}
+			out := make(map[string]interface{}, len(values))
+			for key, value := range values {
+				out[key] = s.unwrap(schema["values"], namespace, value)
+			}
+			return out
+		}
+	}
+
+	return native
+}
+
+// wrap converts value, a plain value or one decoded from json, into the
+// native form goavro encodes with the given schema.
+func (s *Schema) wrap(schema interface{}, namespace string, value interface{}) (interface{}, error) {
+	schema, namespace = s.resolve(schema, namespace)
+
+	switch schema := schema.(type) {
+	case string:
+		return wrapPrimitive(schema, value)
+	case []interface{}:
+		if value == nil {
+			return nil, nil
+		}
+		var errs []string
+		for _, branch := range schema {
+			branchName := s.branchName(branch, namespace)
+			if branchName == "null" {
+				continue
+			}
+			native, err := s.wrap(branch, namespace, value)
+			if err == nil {
+				return goavro.Union(branchName, native), nil
+			}
+			errs = append(errs, err.Error())
+		}
+		return nil, fmt.Errorf("no type of the union matches %v: %v", value, strings.Join(errs, "; "))
+	case map[string]interface{}:
+		switch schema["type"] {
+		case "record", "error":
+			record, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%v is not a record", value)
+			}
+			fields, _ := schema["fields"].([]interface{})
+			native := make(map[string]interface{}, len(fields))
+			for _, field := range fields {
+				field, _ := field.(map[string]interface{})
+				name, _ := field["name"].(string)
+				fieldValue, ok := lookupField(record, name)
+				if !ok {
+					// Left to goavro, which uses the default value.
+					continue
+				}
+				fieldNative, err := s.wrap(field["type"], namespace, fieldValue)
+				if err != nil {
+					return nil, fmt.Errorf("field %v: %v", name, err)
+				}
+				native[name] = fieldNative
+			}
+			return native, nil
+		case "array":
+			items, ok := value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%v is not an array", value)
+			}
+			native := make([]interface{}, len(items))
+			for i, item := range items {
+				itemNative, err := s.wrap(schema["items"], namespace, item)
+				if err != nil {
+					return nil, err
+				}
+				native[i] = itemNative
+			}
+			return native, nil
+		case "map":
+			values, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%v is not a map", value)
+			}
+			native := make(map[string]interface{}, len(values))
+			for key, value := range values {
+				valueNative, err := s.wrap(schema["values"], namespace, value)
+				if err != nil {
+					return nil, err
+				}
+				native[key] = valueNative
+			}
+			return native, nil
+		case "enum":
+			return wrapPrimitive("string", value)
+		case "fixed":
+			return wrapPrimitive("bytes", value)
+		default:
+			// A primitive type, possibly with a logical type.
+			return s.wrap(schema["type"], namespace, value)
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported schema %v", schema)
+}
+
+// branchName returns the name goavro gives a branch of a union.
+func (s *Schema) branchName(branch interface{}, namespace string) string {
+	switch branch := branch.(type) {
+	case string:
+		if _, ok := s.named[branch]; ok || namespace == "" {
+			return branch
+		}
+		if _, ok := s.named[namespace+"."+branch]; ok {
+			return namespace + "." + branch
+		}
+		return branch
+	case map[string]interface{}:
+		switch branch["type"] {
+		case "record", "error", "enum", "fixed":
+			return fullName(branch, namespace)
+		case "array", "map":
+			return branch["type"].(string)
+		default:
+			// A primitive type, possibly with a logical type.
+			name, _ := branch["type"].(string)
+			if logicalType, ok := branch["logicalType"].(string); ok {
+				return name + "." + logicalType
+			}
+			return name
+		}
+	}
+
+	return ""
+}
+
+// wrapPrimitive converts value into the Go type goavro expects for a
+// primitive type.
+func wrapPrimitive(typeName string, value interface{}) (interface{}, error) {
+	if number, ok := value.(json.Number); ok {
+		switch typeName {
+		case "int", "long":
+			n, err := number.Int64()
+			if err != nil {
+				return nil, fmt.Errorf("%v is not an integer", value)
+			}
+			value = n
+		case "float", "double":
+			f, err := number.Float64()
+			if err != nil {
+				return nil, fmt.Errorf("%v is not a number", value)
+			}
+			value = f
+		}
+	}
+
+	switch typeName {
+	case "null":
+		if value != nil {
+			return nil, fmt.Errorf("%v is not null", value)
+		}
+		return nil, nil
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return nil, fmt.Errorf("%v is not a boolean", value)
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return nil, fmt.Errorf("%v is not a string", value)
+		}
+	case "bytes":
+		if s, ok := value.(string); ok {
+			return []byte(s), nil
+		}
+		if _, ok := value.([]byte); !ok {
+			return nil, fmt.Errorf("%v is not bytes", value)
+		}
+	case "int", "long", "float", "double":
+		switch value.(type) {
+		case int, int32, int64, float32, float64:
+		default:
+			return nil, fmt.Errorf("%v is not a number", value)
+		}
+	}
+
+	return value, nil
+}
+
+// lookupField returns the value of the named field, matching its name case
+// insensitively like forcejson does.
+func lookupField(record map[string]interface{}, name string) (interface{}, bool) {
+	if value, ok := record[name]; ok {
+		return value, true
+	}
+	for key, value := range record {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+
+	return nil, false
+}
+
+// fullName returns the full name of a named type defined in namespace.
+func fullName(schema map[string]interface{}, namespace string) string {
+	name, _ := schema["name"].(string)
+	if strings.Contains(name, ".") {
+		return name
+	}
+	if ns, ok := schema["namespace"].(string); ok {
+		namespace = ns
+	}
+	if namespace == "" {
+		return name
+	}
+
+	return namespace + "." + name
+}
+
+func namespaceOf(fullName string) string {
+	if i := strings.LastIndex(fullName, "."); i >= 0 {
+		return fullName[:i]
+	}
+
+	return ""
+}
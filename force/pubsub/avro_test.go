@@ -0,0 +1,127 @@
+package pubsub_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/pubsub"
+)
+
+const accountChangeSchema = `{
+	"type": "record",
+	"name": "AccountChangeEvent",
+	"namespace": "com.sforce.eventbus",
+	"fields": [
+		{"name": "ChangeEventHeader", "type": {
+			"type": "record",
+			"name": "ChangeEventHeader",
+			"fields": [
+				{"name": "entityName", "type": "string"},
+				{"name": "recordIds", "type": {"type": "array", "items": "string"}},
+				{"name": "changeType", "type": {"type": "enum", "name": "ChangeType", "symbols": ["CREATE", "UPDATE", "DELETE"]}},
+				{"name": "commitTimestamp", "type": "long"},
+				{"name": "changedFields", "type": {"type": "array", "items": "string"}}
+			]
+		}},
+		{"name": "Name", "type": ["null", "string"], "default": null},
+		{"name": "BillingAddress", "type": ["null", {
+			"type": "record",
+			"name": "Address",
+			"fields": [
+				{"name": "City", "type": ["null", "string"], "default": null},
+				{"name": "Country", "type": ["null", "string"], "default": null}
+			]
+		}], "default": null},
+		{"name": "NumberOfEmployees", "type": ["null", "int"], "default": null},
+		{"name": "Owner", "type": ["null", "Address"], "default": null}
+	]
+}`
+
+type Address struct {
+	City    string `force:",omitempty"`
+	Country string `force:",omitempty"`
+}
+
+type AccountChange struct {
+	ChangeEventHeader force.ChangeEventHeader
+	Name              string   `force:",omitempty"`
+	BillingAddress    *Address `force:",omitempty"`
+	NumberOfEmployees int      `force:",omitempty"`
+}
+
+var _ = Describe("Schema", func() {
+	var schema *pubsub.Schema
+
+	BeforeEach(func() {
+		var err error
+		schema, err = pubsub.NewSchema("schema2", accountChangeSchema)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject invalid schemas", func() {
+		_, err := pubsub.NewSchema("invalid", `{"type": "record"}`)
+		Expect(err).To(MatchError(ContainSubstring("invalid")))
+	})
+
+	It("should decode unions into plain values", func() {
+		payload, err := schema.Encode(map[string]interface{}{
+			"ChangeEventHeader": map[string]interface{}{
+				"entityName":      "Account",
+				"recordIds":       []interface{}{"001A"},
+				"changeType":      "UPDATE",
+				"commitTimestamp": int64(1700000000000),
+				"changedFields":   []interface{}{"Name", "BillingAddress.City"},
+			},
+			"Name":              "Acme",
+			"BillingAddress":    map[string]interface{}{"City": "Paris"},
+			"NumberOfEmployees": int32(250),
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		fields, err := schema.Decode(payload)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fields).To(Equal(map[string]interface{}{
+			"ChangeEventHeader": map[string]interface{}{
+				"entityName":      "Account",
+				"recordIds":       []interface{}{"001A"},
+				"changeType":      "UPDATE",
+				"commitTimestamp": int64(1700000000000),
+				"changedFields":   []interface{}{"Name", "BillingAddress.City"},
+			},
+			"Name":              "Acme",
+			"BillingAddress":    map[string]interface{}{"City": "Paris", "Country": nil},
+			"NumberOfEmployees": int32(250),
+			"Owner":             nil,
+		}))
+	})
+
+	It("should encode and unmarshal structs with force tags", func() {
+		in := &AccountChange{
+			ChangeEventHeader: force.ChangeEventHeader{
+				EntityName:      "Account",
+				RecordIds:       []string{"001A"},
+				ChangeType:      force.ChangeTypeCreate,
+				CommitTimestamp: 1700000000000,
+				ChangedFields:   []string{},
+			},
+			Name:              "Acme",
+			BillingAddress:    &Address{City: "Paris", Country: "France"},
+			NumberOfEmployees: 250,
+		}
+
+		payload, err := schema.Encode(in)
+		Expect(err).NotTo(HaveOccurred())
+
+		out := &AccountChange{}
+		Expect(schema.Unmarshal(payload, out)).To(Succeed())
+		Expect(out).To(Equal(in))
+	})
+
+	It("should report values that don't match the schema", func() {
+		_, err := schema.Encode(map[string]interface{}{
+			"ChangeEventHeader": map[string]interface{}{"entityName": 12},
+		})
+		Expect(err).To(MatchError(ContainSubstring("entityName")))
+	})
+})
@@ -0,0 +1,222 @@
+// Package pubsub is a client of the Salesforce Pub/Sub API, which publishes
+// and subscribes to platform events and change events over gRPC, encoding
+// them with Avro. It authenticates with the access token of a force.ForceApi
+// and obtains a new one when it expires.
+//
+//	client, err := pubsub.Dial(forceApi, pubsub.DefaultEndpoint)
+//	if err != nil {
+//		return err
+//	}
+//	defer client.Close()
+//
+//	return client.Subscribe(ctx, pubsub.Subscription{TopicName: "/event/Order_Placed__e"}, func(event *pubsub.Event) error {
+//		order := &OrderPlaced{}
+//		if err := event.Unmarshal(order); err != nil {
+//			return err
+//		}
+//		...
+//	})
+package pubsub
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/pubsub/eventbus"
+)
+
+const (
+	// DefaultEndpoint is the address of the Pub/Sub API.
+	DefaultEndpoint = "api.pubsub.salesforce.com:7443"
+
+	accessTokenHeader = "accesstoken"
+	instanceUrlHeader = "instanceurl"
+	tenantIdHeader    = "tenantid"
+)
+
+// Client calls the Pub/Sub API on behalf of the user of a ForceApi. It is
+// safe for concurrent use.
+type Client struct {
+	forceApi *force.ForceApi
+	pubsub   eventbus.PubSubClient
+
+	// conn is closed by Close when the client dialed it.
+	conn *grpc.ClientConn
+
+	mu sync.Mutex
+	// organizationId and userId identify the user of the access token.
+	organizationId string
+	userId         string
+	schemas        map[string]*Schema
+}
+
+// Dial connects to the Pub/Sub API at endpoint, usually DefaultEndpoint,
+// over TLS. opts are applied after the transport credentials, and may
+// replace them.
+func Dial(forceApi *force.ForceApi, endpoint string, opts ...grpc.DialOption) (*Client, error) {
+	opts = append([]grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{}))}, opts...)
+
+	conn, err := grpc.Dial(endpoint, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to connect to the Pub/Sub API: %v", err)
+	}
+
+	client := NewClient(forceApi, conn)
+	client.conn = conn
+	return client, nil
+}
+
+// NewClient returns a client calling the Pub/Sub API over conn, which is
+// left open by Close.
+func NewClient(forceApi *force.ForceApi, conn grpc.ClientConnInterface) *Client {
+	return &Client{
+		forceApi: forceApi,
+		pubsub:   eventbus.NewPubSubClient(conn),
+		schemas:  make(map[string]*Schema),
+	}
+}
+
+// Close closes the connection opened by Dial.
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+
+	return c.conn.Close()
+}
+
+// GetTopic returns the information of a topic, such as
+// "/event/Order_Placed__e" or "/data/AccountChangeEvent", including the id of
+// its current schema.
+func (c *Client) GetTopic(ctx context.Context, topicName string) (*eventbus.TopicInfo, error) {
+	var topic *eventbus.TopicInfo
+	err := c.invoke(ctx, func(ctx context.Context) (err error) {
+		topic, err = c.pubsub.GetTopic(ctx, &eventbus.TopicRequest{TopicName: topicName})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return topic, nil
+}
+
+// GetSchema returns the Avro schema with the given id. Schemas are cached,
+// since they never change.
+func (c *Client) GetSchema(ctx context.Context, schemaId string) (*Schema, error) {
+	c.mu.Lock()
+	schema, ok := c.schemas[schemaId]
+	c.mu.Unlock()
+	if ok {
+		return schema, nil
+	}
+
+	var info *eventbus.SchemaInfo
+	err := c.invoke(ctx, func(ctx context.Context) (err error) {
+		info, err = c.pubsub.GetSchema(ctx, &eventbus.SchemaRequest{SchemaId: schemaId})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err = NewSchema(schemaId, info.SchemaJson)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.schemas[schemaId] = schema
+	c.mu.Unlock()
+
+	return schema, nil
+}
+
+// invoke calls rpc with the authentication metadata, and calls it again with
+// a new access token when the current one expired.
+func (c *Client) invoke(ctx context.Context, rpc func(ctx context.Context) error) error {
+	for renewed := false; ; renewed = true {
+		authCtx, accessToken, err := c.authContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = rpc(authCtx)
+		if renewed || status.Code(err) != codes.Unauthenticated {
+			return err
+		}
+
+		if err := c.forceApi.RenewAccessTokenContext(ctx, accessToken); err != nil {
+			return err
+		}
+	}
+}
+
+// authContext returns ctx along with the metadata authenticating calls, and
+// the access token it holds.
+func (c *Client) authContext(ctx context.Context) (context.Context, string, error) {
+	organizationId, _, err := c.identity(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	accessToken := c.forceApi.GetAccessToken()
+	ctx = metadata.AppendToOutgoingContext(ctx,
+		accessTokenHeader, accessToken,
+		instanceUrlHeader, c.forceApi.GetInstanceURL(),
+		tenantIdHeader, organizationId,
+	)
+
+	return ctx, accessToken, nil
+}
+
+// identity returns the ids of the organization and the user of the access
+// token, from its identity url when known and from force.com otherwise.
+func (c *Client) identity(ctx context.Context) (string, string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.organizationId != "" {
+		return c.organizationId, c.userId, nil
+	}
+
+	if organizationId, userId, ok := parseIdentityUrl(c.forceApi.GetIdentityURL()); ok {
+		c.organizationId, c.userId = organizationId, userId
+		return c.organizationId, c.userId, nil
+	}
+
+	userInfo, err := c.forceApi.UserInfoContext(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("Unable to get the organization id: %v", err)
+	}
+
+	c.organizationId, c.userId = userInfo.OrganizationId, userInfo.UserId
+	return c.organizationId, c.userId, nil
+}
+
+// parseIdentityUrl extracts the ids of an identity url of the form
+// https://login.salesforce.com/id/<organization id>/<user id>.
+func parseIdentityUrl(identityUrl string) (string, string, bool) {
+	u, err := url.Parse(identityUrl)
+	if err != nil {
+		return "", "", false
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 3 || parts[len(parts)-3] != "id" {
+		return "", "", false
+	}
+
+	return parts[len(parts)-2], parts[len(parts)-1], true
+}
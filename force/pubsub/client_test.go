@@ -0,0 +1,190 @@
+package pubsub_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/pubsub"
+	"github.com/opendoor-labs/go-force/force/pubsub/eventbus"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		fake     *fakePubSub
+		client   *pubsub.Client
+		forceApi *force.ForceApi
+		stop     func()
+		schema   *pubsub.Schema
+	)
+
+	BeforeEach(func() {
+		fake = newFakePubSub()
+		client, forceApi, stop = startFakePubSub(fake)
+
+		var err error
+		schema, err = pubsub.NewSchema("schema1", orderSchema)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		stop()
+	})
+
+	orderEvent := func(replayId byte, orderNumber string) *eventbus.ConsumerEvent {
+		payload, err := schema.Encode(map[string]interface{}{
+			"CreatedDate":     int64(1700000000000),
+			"CreatedById":     "005000000000001",
+			"Order_Number__c": orderNumber,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		return &eventbus.ConsumerEvent{
+			ReplayId: []byte{replayId},
+			Event:    &eventbus.ProducerEvent{Id: "uuid-" + orderNumber, SchemaId: "schema1", Payload: payload},
+		}
+	}
+
+	It("should authenticate with the access token of the ForceApi", func() {
+		topic, err := client.GetTopic(context.Background(), "/event/Order_Placed__e")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(topic.SchemaId).To(Equal("schema1"))
+		Expect(topic.CanPublish).To(BeTrue())
+
+		md := fake.metadata[0]
+		Expect(md.Get("accesstoken")).To(Equal([]string{"at1"}))
+		Expect(md.Get("instanceurl")).To(Equal([]string{"https://example.my.salesforce.com"}))
+		Expect(md.Get("tenantid")).To(Equal([]string{"00D000000000001"}))
+	})
+
+	It("should return errors", func() {
+		_, err := client.GetTopic(context.Background(), "/event/Unknown__e")
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+	})
+
+	It("should renew the access token when it expires", func() {
+		fake.expired["at1"] = true
+
+		_, err := client.GetTopic(context.Background(), "/event/Order_Placed__e")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(forceApi.GetAccessToken()).To(Equal("at2"))
+		Expect(fake.metadata).To(HaveLen(2))
+		Expect(fake.metadata[1].Get("accesstoken")).To(Equal([]string{"at2"}))
+	})
+
+	It("should cache schemas", func() {
+		first, err := client.GetSchema(context.Background(), "schema1")
+		Expect(err).NotTo(HaveOccurred())
+		second, err := client.GetSchema(context.Background(), "schema1")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(second).To(BeIdenticalTo(first))
+		Expect(fake.metadata).To(HaveLen(1))
+	})
+
+	It("should publish events", func() {
+		before := time.Now().UnixNano() / int64(time.Millisecond)
+		results, err := client.Publish(context.Background(), "/event/Order_Placed__e",
+			&OrderPlaced{OrderNumber: "O-1", Amount: 12.5},
+			map[string]interface{}{"Order_Number__c": "O-2", "CreatedById": "005000000000002"},
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Error).To(BeNil())
+		Expect(results[0].ReplayId).To(Equal([]byte{1}))
+
+		req := fake.published[0]
+		Expect(req.TopicName).To(Equal("/event/Order_Placed__e"))
+		Expect(req.Events).To(HaveLen(2))
+		Expect(results[0].CorrelationKey).To(Equal(req.Events[0].Id))
+		Expect(req.Events[0].Id).To(MatchRegexp(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`))
+		Expect(req.Events[0].SchemaId).To(Equal("schema1"))
+
+		fields, err := schema.Decode(req.Events[0].Payload)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fields["CreatedDate"]).To(BeNumerically(">=", before))
+		Expect(fields).To(HaveKeyWithValue("CreatedById", "005000000000001"))
+		Expect(fields).To(HaveKeyWithValue("Order_Number__c", "O-1"))
+		Expect(fields).To(HaveKeyWithValue("Amount__c", 12.5))
+
+		fields, err = schema.Decode(req.Events[1].Payload)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fields).To(HaveKeyWithValue("CreatedById", "005000000000002"))
+		Expect(fields).To(HaveKeyWithValue("Amount__c", BeNil()))
+	})
+
+	It("should subscribe with flow control", func() {
+		fake.events = []*eventbus.ConsumerEvent{
+			orderEvent(1, "O-1"), orderEvent(2, "O-2"), orderEvent(3, "O-3"), orderEvent(4, "O-4"), orderEvent(5, "O-5"),
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var orders []string
+		err := client.Subscribe(ctx, pubsub.Subscription{
+			TopicName:    "/event/Order_Placed__e",
+			ReplayPreset: eventbus.ReplayPreset_EARLIEST,
+			BatchSize:    2,
+		}, func(event *pubsub.Event) error {
+			order := &OrderPlaced{}
+			if err := event.Unmarshal(order); err != nil {
+				return err
+			}
+			Expect(event.Id).To(Equal("uuid-" + order.OrderNumber))
+			Expect(order.CreatedById).To(Equal("005000000000001"))
+
+			orders = append(orders, order.OrderNumber)
+			if len(orders) == 5 {
+				cancel()
+			}
+			return nil
+		})
+		Expect(err).To(Equal(context.Canceled))
+		Expect(orders).To(Equal([]string{"O-1", "O-2", "O-3", "O-4", "O-5"}))
+
+		fetches := fake.fetchRequests()
+		Expect(fetches).To(HaveLen(3))
+		Expect(fetches[0].TopicName).To(Equal("/event/Order_Placed__e"))
+		Expect(fetches[0].ReplayPreset).To(Equal(eventbus.ReplayPreset_EARLIEST))
+		for _, fetch := range fetches {
+			Expect(fetch.NumRequested).To(Equal(int32(2)))
+		}
+	})
+
+	It("should resume after the last event handled when the access token expires", func() {
+		fake.events = []*eventbus.ConsumerEvent{orderEvent(1, "O-1"), orderEvent(2, "O-2"), orderEvent(3, "O-3")}
+		fake.expireAfter = 2
+
+		stop := errors.New("stop")
+		var orders []string
+		err := client.Subscribe(context.Background(), pubsub.Subscription{
+			TopicName:    "/event/Order_Placed__e",
+			ReplayPreset: eventbus.ReplayPreset_EARLIEST,
+		}, func(event *pubsub.Event) error {
+			fields, err := event.Decode()
+			if err != nil {
+				return err
+			}
+			orders = append(orders, fields["Order_Number__c"].(string))
+			if len(orders) == 3 {
+				return stop
+			}
+			return nil
+		})
+		Expect(err).To(Equal(stop))
+		Expect(orders).To(Equal([]string{"O-1", "O-2", "O-3"}))
+		Expect(forceApi.GetAccessToken()).To(Equal("at2"))
+
+		fetches := fake.fetchRequests()
+		Expect(fetches[len(fetches)-1].ReplayPreset).To(Equal(eventbus.ReplayPreset_CUSTOM))
+		Expect(fetches[len(fetches)-1].ReplayId).To(Equal([]byte{2}))
+		Expect(fetches[len(fetches)-1].NumRequested).To(Equal(int32(pubsub.DefaultBatchSize)))
+	})
+})
@@ -0,0 +1,6 @@
+// Package eventbus holds the gRPC client and server generated from the
+// definition of the Salesforce Pub/Sub API. Use package pubsub rather than
+// this package directly.
+package eventbus
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative pubsub_api.proto
@@ -0,0 +1,102 @@
+package pubsub
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/opendoor-labs/go-force/force/pubsub/eventbus"
+)
+
+// Publish publishes events to a topic, such as "/event/Order_Placed__e",
+// encoding them with its current schema. Each event is a map of fields or a
+// struct with force tags; see Schema.Encode. CreatedDate and CreatedById,
+// which platform events require, default to now and to the user of the
+// access token.
+//
+// The returned results are aligned with events. Each holds either the replay
+// id of the published event or the error that kept it from being published,
+// and the id given to the event as its correlation key.
+func (c *Client) Publish(ctx context.Context, topicName string, events ...interface{}) ([]*eventbus.PublishResult, error) {
+	topic, err := c.GetTopic(ctx, topicName)
+	if err != nil {
+		return nil, err
+	}
+	if !topic.CanPublish {
+		return nil, fmt.Errorf("Unable to publish to %v: not allowed", topicName)
+	}
+
+	schema, err := c.GetSchema(ctx, topic.SchemaId)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults, err := c.publishDefaults(ctx, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &eventbus.PublishRequest{TopicName: topicName}
+	for _, event := range events {
+		payload, err := schema.encode(event, defaults)
+		if err != nil {
+			return nil, err
+		}
+
+		id, err := newEventId()
+		if err != nil {
+			return nil, err
+		}
+
+		req.Events = append(req.Events, &eventbus.ProducerEvent{
+			Id:       id,
+			SchemaId: schema.Id,
+			Payload:  payload,
+		})
+	}
+
+	var resp *eventbus.PublishResponse
+	err = c.invoke(ctx, func(ctx context.Context) (err error) {
+		resp, err = c.pubsub.Publish(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Results, nil
+}
+
+// publishDefaults returns the values of the fields platform events require
+// that are known without the caller.
+func (c *Client) publishDefaults(ctx context.Context, schema *Schema) (map[string]interface{}, error) {
+	defaults := make(map[string]interface{})
+
+	if schema.hasField("CreatedDate") {
+		defaults["CreatedDate"] = time.Now().UnixNano() / int64(time.Millisecond)
+	}
+
+	if schema.hasField("CreatedById") {
+		_, userId, err := c.identity(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defaults["CreatedById"] = userId
+	}
+
+	return defaults, nil
+}
+
+// newEventId returns a random uuid to correlate an event with its publish
+// result.
+func newEventId() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("Unable to generate an event id: %v", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
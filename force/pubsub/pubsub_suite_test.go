@@ -0,0 +1,244 @@
+package pubsub_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/forcefakes"
+	"github.com/opendoor-labs/go-force/force/pubsub"
+	"github.com/opendoor-labs/go-force/force/pubsub/eventbus"
+)
+
+func TestPubsub(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Pubsub Suite")
+}
+
+const orderSchema = `{
+	"type": "record",
+	"name": "Order_Placed__e",
+	"namespace": "com.sforce.eventbus",
+	"fields": [
+		{"name": "CreatedDate", "type": "long", "doc": "CreatedDate:DateTime"},
+		{"name": "CreatedById", "type": "string", "doc": "CreatedBy:EntityId"},
+		{"name": "Order_Number__c", "type": ["null", "string"], "doc": "Data:Text", "default": null},
+		{"name": "Amount__c", "type": ["null", "double"], "doc": "Data:Double", "default": null}
+	]
+}`
+
+type OrderPlaced struct {
+	CreatedById string  `force:",omitempty"`
+	OrderNumber string  `force:"Order_Number__c,omitempty"`
+	Amount      float64 `force:"Amount__c,omitempty"`
+}
+
+// fakePubSub is an in-process Pub/Sub API serving the events of a single
+// topic.
+type fakePubSub struct {
+	eventbus.UnimplementedPubSubServer
+
+	mu sync.Mutex
+	// events are streamed to subscribers, starting after the replay id
+	// they ask for.
+	events []*eventbus.ConsumerEvent
+	// expireAfter expires the access token of a subscriber once it was
+	// sent that many events, when not zero.
+	expireAfter int
+	expired     map[string]bool
+
+	metadata  []metadata.MD
+	fetches   []*eventbus.FetchRequest
+	published []*eventbus.PublishRequest
+}
+
+func newFakePubSub() *fakePubSub {
+	return &fakePubSub{expired: make(map[string]bool)}
+}
+
+func (f *fakePubSub) authenticate(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.metadata = append(f.metadata, md)
+	if tokens := md.Get("accesstoken"); len(tokens) != 1 || f.expired[tokens[0]] {
+		return status.Error(codes.Unauthenticated, "invalid access token")
+	}
+
+	return nil
+}
+
+func (f *fakePubSub) expire(ctx context.Context) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.expired[md.Get("accesstoken")[0]] = true
+}
+
+func (f *fakePubSub) GetTopic(ctx context.Context, req *eventbus.TopicRequest) (*eventbus.TopicInfo, error) {
+	if err := f.authenticate(ctx); err != nil {
+		return nil, err
+	}
+	if req.TopicName != "/event/Order_Placed__e" {
+		return nil, status.Error(codes.NotFound, "topic not found")
+	}
+
+	return &eventbus.TopicInfo{
+		TopicName:    req.TopicName,
+		CanPublish:   true,
+		CanSubscribe: true,
+		SchemaId:     "schema1",
+	}, nil
+}
+
+func (f *fakePubSub) GetSchema(ctx context.Context, req *eventbus.SchemaRequest) (*eventbus.SchemaInfo, error) {
+	if err := f.authenticate(ctx); err != nil {
+		return nil, err
+	}
+
+	return &eventbus.SchemaInfo{SchemaId: req.SchemaId, SchemaJson: orderSchema}, nil
+}
+
+func (f *fakePubSub) Publish(ctx context.Context, req *eventbus.PublishRequest) (*eventbus.PublishResponse, error) {
+	if err := f.authenticate(ctx); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	f.published = append(f.published, req)
+	f.mu.Unlock()
+
+	resp := &eventbus.PublishResponse{SchemaId: "schema1"}
+	for i, event := range req.Events {
+		result := &eventbus.PublishResult{CorrelationKey: event.Id}
+		if event.SchemaId != "schema1" {
+			result.Error = &eventbus.Error{Code: eventbus.ErrorCode_PUBLISH, Msg: "unknown schema"}
+		} else {
+			result.ReplayId = []byte{byte(i + 1)}
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	return resp, nil
+}
+
+func (f *fakePubSub) Subscribe(stream eventbus.PubSub_SubscribeServer) error {
+	if err := f.authenticate(stream.Context()); err != nil {
+		return err
+	}
+
+	sent := 0
+	next := 0
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		f.mu.Lock()
+		f.fetches = append(f.fetches, req)
+		events := f.events
+		expireAfter := f.expireAfter
+		f.mu.Unlock()
+
+		if sent == 0 && req.ReplayPreset == eventbus.ReplayPreset_CUSTOM {
+			for i, event := range events {
+				if bytes.Equal(event.ReplayId, req.ReplayId) {
+					next = i + 1
+				}
+			}
+		}
+
+		for pending := req.NumRequested; pending > 0; {
+			if next == len(events) {
+				// Keep the stream alive until the subscriber goes away.
+				if err := stream.Send(&eventbus.FetchResponse{PendingNumRequested: pending}); err != nil {
+					return err
+				}
+				<-stream.Context().Done()
+				return stream.Context().Err()
+			}
+			if expireAfter != 0 && sent == expireAfter {
+				f.expire(stream.Context())
+				return status.Error(codes.Unauthenticated, "access token expired")
+			}
+
+			pending--
+			err := stream.Send(&eventbus.FetchResponse{
+				Events:              []*eventbus.ConsumerEvent{events[next]},
+				LatestReplayId:      events[next].ReplayId,
+				PendingNumRequested: pending,
+			})
+			if err != nil {
+				return err
+			}
+			next++
+			sent++
+		}
+	}
+}
+
+func (f *fakePubSub) fetchRequests() []*eventbus.FetchRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]*eventbus.FetchRequest(nil), f.fetches...)
+}
+
+// startFakePubSub serves fake over an in-memory listener, and returns a
+// client connected to it, authenticated with tokens issued by a fake login
+// endpoint: "at1", then "at2" and so on. stop closes both.
+func startFakePubSub(fake *fakePubSub) (client *pubsub.Client, forceApi *force.ForceApi, stop func()) {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	eventbus.RegisterPubSubServer(server, fake)
+	go server.Serve(listener)
+
+	logins := 0
+	httpClient := &forcefakes.FakeHttpClient{}
+	httpClient.DoStub = func(req *http.Request) (*http.Response, error) {
+		logins++
+		body := fmt.Sprintf(`{"access_token": "at%v", "instance_url": "https://example.my.salesforce.com", "id": "https://login.salesforce.com/id/00D000000000001/005000000000001"}`, logins)
+		return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(bytes.NewBufferString(body))}, nil
+	}
+
+	var err error
+	forceApi, err = force.New(
+		force.WithHttpClient(httpClient),
+		force.WithPassword("id", "secret", "user", "pass", "token"),
+		force.WithLazyMetadata(),
+	)
+	Expect(err).NotTo(HaveOccurred())
+
+	client, err = pubsub.Dial(forceApi, "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	Expect(err).NotTo(HaveOccurred())
+
+	return client, forceApi, func() {
+		client.Close()
+		server.Stop()
+	}
+}
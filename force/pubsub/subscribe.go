@@ -0,0 +1,168 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/opendoor-labs/go-force/force/pubsub/eventbus"
+)
+
+// DefaultBatchSize is the number of events a subscription requests at a
+// time unless told otherwise.
+const DefaultBatchSize = 100
+
+// Subscription is a topic to subscribe to, such as "/event/Order_Placed__e",
+// "/data/AccountChangeEvent" or "/data/ChangeEvents".
+type Subscription struct {
+	TopicName string
+	// ReplayPreset starts the subscription at the events published from now
+	// on by default, at the earliest events retained with
+	// eventbus.ReplayPreset_EARLIEST, or after ReplayId with
+	// eventbus.ReplayPreset_CUSTOM.
+	ReplayPreset eventbus.ReplayPreset
+	ReplayId     []byte
+	// BatchSize is the number of events requested at a time. More are
+	// requested once they have all been handled, so that a slow handler
+	// isn't sent more events than it can keep up with. It defaults to
+	// DefaultBatchSize.
+	BatchSize int32
+}
+
+// Event is an event received from a subscription.
+type Event struct {
+	// Id is the EventUuid of the event.
+	Id string
+	// ReplayId resumes a subscription after the event.
+	ReplayId []byte
+	Schema   *Schema
+	Payload  []byte
+}
+
+// Decode decodes the payload of the event into a map of its fields. See
+// Schema.Decode.
+func (e *Event) Decode() (map[string]interface{}, error) {
+	return e.Schema.Decode(e.Payload)
+}
+
+// Unmarshal decodes the payload of the event into out, a struct with force
+// tags.
+func (e *Event) Unmarshal(out interface{}) error {
+	return e.Schema.Unmarshal(e.Payload, out)
+}
+
+// Subscribe receives the events of a topic and calls handler with each of
+// them in turn, until ctx is done, handler returns an error, or the stream
+// fails, and returns why it stopped. When the access token expires, a new
+// one is obtained and the subscription resumes after the last event handled.
+func (c *Client) Subscribe(ctx context.Context, subscription Subscription, handler func(*Event) error) error {
+	if subscription.BatchSize <= 0 {
+		subscription.BatchSize = DefaultBatchSize
+	}
+
+	renewed := false
+	for {
+		handled, accessToken, err := c.subscribe(ctx, &subscription, handler)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if handled {
+			renewed = false
+		}
+		// Give up when a new token didn't get any event through either.
+		if accessToken == "" || status.Code(err) != codes.Unauthenticated || renewed {
+			return err
+		}
+
+		if err := c.forceApi.RenewAccessTokenContext(ctx, accessToken); err != nil {
+			return err
+		}
+		renewed = true
+	}
+}
+
+// subscribe makes a single Subscribe call, moving subscription past the
+// events handled. It returns whether any event was handled, and the access
+// token it used when it is worth trying again with a new one.
+func (c *Client) subscribe(ctx context.Context, subscription *Subscription, handler func(*Event) error) (bool, string, error) {
+	authCtx, accessToken, err := c.authContext(ctx)
+	if err != nil {
+		return false, "", err
+	}
+
+	authCtx, cancel := context.WithCancel(authCtx)
+	defer cancel()
+
+	stream, err := c.pubsub.Subscribe(authCtx)
+	if err != nil {
+		return false, accessToken, err
+	}
+
+	err = stream.Send(&eventbus.FetchRequest{
+		TopicName:    subscription.TopicName,
+		ReplayPreset: subscription.ReplayPreset,
+		ReplayId:     subscription.ReplayId,
+		NumRequested: subscription.BatchSize,
+	})
+	if err != nil {
+		return false, accessToken, err
+	}
+
+	handled := false
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return handled, accessToken, err
+		}
+
+		for _, consumed := range resp.Events {
+			event, err := c.event(ctx, consumed)
+			if err != nil {
+				return handled, "", err
+			}
+
+			if err := handler(event); err != nil {
+				return handled, "", err
+			}
+
+			handled = true
+			subscription.ReplayPreset = eventbus.ReplayPreset_CUSTOM
+			subscription.ReplayId = consumed.ReplayId
+		}
+
+		// Empty responses only keep the stream alive while no event is
+		// published, and leave the pending events as they were.
+		if len(resp.Events) == 0 || resp.PendingNumRequested > 0 {
+			continue
+		}
+
+		err = stream.Send(&eventbus.FetchRequest{
+			TopicName:    subscription.TopicName,
+			NumRequested: subscription.BatchSize,
+		})
+		if err != nil {
+			return handled, accessToken, err
+		}
+	}
+}
+
+func (c *Client) event(ctx context.Context, consumed *eventbus.ConsumerEvent) (*Event, error) {
+	producerEvent := consumed.GetEvent()
+	if producerEvent == nil {
+		return nil, fmt.Errorf("Received an event without payload")
+	}
+
+	schema, err := c.GetSchema(ctx, producerEvent.SchemaId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Event{
+		Id:       producerEvent.Id,
+		ReplayId: consumed.ReplayId,
+		Schema:   schema,
+		Payload:  producerEvent.Payload,
+	}, nil
+}
@@ -1,6 +1,7 @@
 package force
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strings"
@@ -22,13 +23,18 @@ func BuildQuery(fields, table string, constraints []string) string {
 // Use the Query resource to execute a SOQL query that returns all the results in a single response,
 // or if needed, returns part of the results and an identifier used to retrieve the remaining results.
 func (forceApi *ForceApi) Query(query string, out interface{}) (err error) {
-	uri := forceApi.apiResources[queryKey]
+	return forceApi.QueryContext(context.Background(), query, out)
+}
+
+// QueryContext is like Query but carries ctx to the underlying http request.
+func (forceApi *ForceApi) QueryContext(ctx context.Context, query string, out interface{}) (err error) {
+	uri := forceApi.resourceUrl(queryKey)
 
 	params := url.Values{
 		"q": {query},
 	}
 
-	_, err = forceApi.Get(uri, params, out)
+	_, err = forceApi.GetContext(ctx, uri, params, out)
 
 	return
 }
@@ -37,19 +43,29 @@ func (forceApi *ForceApi) Query(query string, out interface{}) (err error) {
 // been deleted because of a merge or delete. Use QueryAll rather than Query, because the Query resource
 // will automatically filter out items that have been deleted.
 func (forceApi *ForceApi) QueryAll(query string, out interface{}) (err error) {
-	uri := forceApi.apiResources[queryAllKey]
+	return forceApi.QueryAllContext(context.Background(), query, out)
+}
+
+// QueryAllContext is like QueryAll but carries ctx to the underlying http request.
+func (forceApi *ForceApi) QueryAllContext(ctx context.Context, query string, out interface{}) (err error) {
+	uri := forceApi.resourceUrl(queryAllKey)
 
 	params := url.Values{
 		"q": {query},
 	}
 
-	_, err = forceApi.Get(uri, params, out)
+	_, err = forceApi.GetContext(ctx, uri, params, out)
 
 	return
 }
 
 func (forceApi *ForceApi) QueryNext(uri string, out interface{}) (err error) {
-	_, err = forceApi.Get(uri, nil, out)
+	return forceApi.QueryNextContext(context.Background(), uri, out)
+}
+
+// QueryNextContext is like QueryNext but carries ctx to the underlying http request.
+func (forceApi *ForceApi) QueryNextContext(ctx context.Context, uri string, out interface{}) (err error) {
+	_, err = forceApi.GetContext(ctx, uri, nil, out)
 
 	return
 }
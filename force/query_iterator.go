@@ -0,0 +1,184 @@
+package force
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/opendoor-labs/go-force/force/parser"
+	"github.com/opendoor-labs/go-force/forcejson"
+	"github.com/opendoor-labs/go-force/sobjects"
+)
+
+// QueryIterator walks the records of a SOQL query one at a time, lazily
+// fetching the following pages through QueryNext as it goes. Use it like
+// sql.Rows:
+//
+//	it := forceApi.NewQueryIterator("SELECT Id, Name FROM Account")
+//	defer it.Close()
+//	for it.Next() {
+//		account := &sobjects.Account{}
+//		if err := it.Decode(account); err != nil {
+//			return err
+//		}
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type QueryIterator struct {
+	forceApi *ForceApi
+	ctx      context.Context
+	query    string
+	all      bool
+
+	started   bool
+	closed    bool
+	page      *queryIteratorPage
+	pos       int
+	totalSize int
+	err       error
+}
+
+type queryIteratorPage struct {
+	sobjects.BaseQuery
+	Records []forcejson.RawMessage `force:"records"`
+}
+
+// NewQueryIterator returns an iterator over every record matched by query.
+// No request is made until the first call to Next.
+func (forceApi *ForceApi) NewQueryIterator(query string) *QueryIterator {
+	return forceApi.NewQueryIteratorContext(context.Background(), query)
+}
+
+// NewQueryIteratorContext is like NewQueryIterator but carries ctx to the underlying http requests.
+func (forceApi *ForceApi) NewQueryIteratorContext(ctx context.Context, query string) *QueryIterator {
+	return &QueryIterator{forceApi: forceApi, ctx: ctx, query: query}
+}
+
+// NewQueryAllIterator is like NewQueryIterator but uses the QueryAll resource,
+// so deleted and archived records are included.
+func (forceApi *ForceApi) NewQueryAllIterator(query string) *QueryIterator {
+	return forceApi.NewQueryAllIteratorContext(context.Background(), query)
+}
+
+// NewQueryAllIteratorContext is like NewQueryAllIterator but carries ctx to the underlying http requests.
+func (forceApi *ForceApi) NewQueryAllIteratorContext(ctx context.Context, query string) *QueryIterator {
+	return &QueryIterator{forceApi: forceApi, ctx: ctx, query: query, all: true}
+}
+
+// Next advances the iterator to the next record, fetching the next page when
+// the current one is exhausted. It returns false when there are no more
+// records, the iterator was closed or an error occurred; check Err to tell
+// these apart.
+func (it *QueryIterator) Next() bool {
+	if it.closed || it.err != nil {
+		return false
+	}
+
+	if !it.started {
+		it.started = true
+		it.fetch(func(page *queryIteratorPage) error {
+			if it.all {
+				return it.forceApi.QueryAllContext(it.ctx, it.query, page)
+			}
+			return it.forceApi.QueryContext(it.ctx, it.query, page)
+		})
+		return it.advance()
+	}
+
+	it.pos++
+	return it.advance()
+}
+
+// advance makes sure pos points at a record, following NextRecordsUri past
+// the end of the current page.
+func (it *QueryIterator) advance() bool {
+	for it.err == nil && it.page != nil {
+		if it.pos < len(it.page.Records) {
+			return true
+		}
+		if it.page.Done || it.page.NextRecordsUri == "" {
+			it.closed = true
+			return false
+		}
+
+		uri := it.page.NextRecordsUri
+		it.fetch(func(page *queryIteratorPage) error {
+			return it.forceApi.QueryNextContext(it.ctx, uri, page)
+		})
+	}
+
+	return false
+}
+
+func (it *QueryIterator) fetch(query func(page *queryIteratorPage) error) {
+	page := &queryIteratorPage{}
+	if err := query(page); err != nil {
+		it.err = err
+		it.page = nil
+		return
+	}
+
+	it.page = page
+	it.pos = 0
+	it.totalSize = int(page.TotalSize)
+}
+
+// Decode unmarshals the current record into out, which is usually a pointer
+// to an SObject, the same way Query does.
+func (it *QueryIterator) Decode(out interface{}) error {
+	if it.page == nil || it.pos >= len(it.page.Records) {
+		return fmt.Errorf("Decode called without a current record")
+	}
+
+	return parser.ParseSFJSON(it.page.Records[it.pos], out)
+}
+
+// TotalSize returns the total number of records matched by the query, as
+// reported by force.com. It is zero until the first call to Next.
+func (it *QueryIterator) TotalSize() int {
+	return it.totalSize
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *QueryIterator) Err() error {
+	return it.err
+}
+
+// Close stops the iteration early. No further pages are fetched. It is
+// idempotent.
+func (it *QueryIterator) Close() {
+	it.closed = true
+	it.page = nil
+}
+
+// QueryAllPages runs query and follows every page of results, appending all
+// records to out, a pointer to a slice of SObjects.
+func (forceApi *ForceApi) QueryAllPages(query string, out interface{}) error {
+	return forceApi.QueryAllPagesContext(context.Background(), query, out)
+}
+
+// QueryAllPagesContext is like QueryAllPages but carries ctx to the underlying http requests.
+func (forceApi *ForceApi) QueryAllPagesContext(ctx context.Context, query string, out interface{}) error {
+	return collectQueryIterator(forceApi.NewQueryIteratorContext(ctx, query), out)
+}
+
+func collectQueryIterator(it *QueryIterator, out interface{}) error {
+	slice := reflect.ValueOf(out)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("Unable to collect query results into %T, expected a pointer to a slice", out)
+	}
+	slice = slice.Elem()
+	elemType := slice.Type().Elem()
+
+	defer it.Close()
+	for it.Next() {
+		elem := reflect.New(elemType)
+		if err := it.Decode(elem.Interface()); err != nil {
+			return fmt.Errorf("Unable to unmarshal query record: %v", err)
+		}
+		slice.Set(reflect.Append(slice, elem.Elem()))
+	}
+
+	return it.Err()
+}
@@ -0,0 +1,99 @@
+package force_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/forcefakes"
+	"github.com/opendoor-labs/go-force/sobjects"
+)
+
+const (
+	firstQueryPage  = `{"totalSize": 3, "done": false, "nextRecordsUrl": "/services/data/v40.0/query/01gD-2000", "records": [{"Id": "001A", "Name": "Acme"}, {"Id": "001B", "Name": "Globex"}]}`
+	secondQueryPage = `{"totalSize": 3, "done": true, "records": [{"Id": "001C", "Name": "Initech"}]}`
+)
+
+var _ = Describe("QueryIterator", func() {
+	var httpClient forcefakes.FakeHttpClient
+	var forceApi *force.ForceApi
+
+	BeforeEach(func() {
+		httpClient = forcefakes.FakeHttpClient{}
+
+		var err error
+		forceApi, err = createForceApi(&httpClient)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should walk every page one record at a time", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(firstQueryPage, 200), nil)
+		httpClient.DoReturnsOnCall(4, NewFakeResponse(secondQueryPage, 200), nil)
+
+		it := forceApi.NewQueryIterator("SELECT Id, Name FROM Account")
+		Expect(httpClient.DoCallCount()).To(Equal(3))
+
+		var names []string
+		for it.Next() {
+			account := sobjects.Account{}
+			Expect(it.Decode(&account)).To(Succeed())
+			names = append(names, account.Name)
+		}
+		Expect(it.Err()).NotTo(HaveOccurred())
+		Expect(it.TotalSize()).To(Equal(3))
+		Expect(names).To(Equal([]string{"Acme", "Globex", "Initech"}))
+		Expect(httpClient.DoArgsForCall(4).URL.Path).To(HaveSuffix("/services/data/v40.0/query/01gD-2000"))
+	})
+
+	It("should decode records the same way Query does", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(secondQueryPage, 200), nil)
+
+		it := forceApi.NewQueryIterator("SELECT Id, Name FROM Account")
+		Expect(it.Next()).To(BeTrue())
+
+		var accounts []*sobjects.Account
+		Expect(it.Decode(&accounts)).To(Succeed())
+		Expect(accounts).To(HaveLen(1))
+		Expect(accounts[0].Name).To(Equal("Initech"))
+	})
+
+	It("should not fetch more pages once closed", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(firstQueryPage, 200), nil)
+
+		it := forceApi.NewQueryIterator("SELECT Id, Name FROM Account")
+		Expect(it.Next()).To(BeTrue())
+		it.Close()
+		Expect(it.Next()).To(BeFalse())
+		Expect(it.Err()).NotTo(HaveOccurred())
+		Expect(httpClient.DoCallCount()).To(Equal(4))
+	})
+
+	It("should surface errors hit while fetching a later page", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(firstQueryPage, 200), nil)
+		httpClient.DoReturnsOnCall(4, nil, errors.New("connection reset"))
+
+		it := forceApi.NewQueryAllIterator("SELECT Id, Name FROM Account")
+		count := 0
+		for it.Next() {
+			count++
+		}
+		Expect(count).To(Equal(2))
+		Expect(it.Err()).To(HaveOccurred())
+		Expect(it.Err().Error()).To(ContainSubstring("connection reset"))
+	})
+
+	Describe("QueryAllPages", func() {
+		It("should accumulate every page into the slice", func() {
+			httpClient.DoReturnsOnCall(3, NewFakeResponse(firstQueryPage, 200), nil)
+			httpClient.DoReturnsOnCall(4, NewFakeResponse(secondQueryPage, 200), nil)
+
+			var accounts []*sobjects.Account
+			err := forceApi.QueryAllPages("SELECT Id, Name FROM Account", &accounts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(accounts).To(HaveLen(3))
+			Expect(accounts[2].Id).To(Equal("001C"))
+		})
+	})
+})
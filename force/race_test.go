@@ -0,0 +1,128 @@
+package force_test
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/forcefakes"
+)
+
+// These specs share one ForceApi across goroutines. They are most useful
+// when run with the race detector: go test -race ./force
+
+type expiringTokenSource struct {
+	refreshes int32
+}
+
+func (s *expiringTokenSource) Token(ctx context.Context) (*force.Token, error) {
+	return &force.Token{AccessToken: "expired", InstanceUrl: "https://example.my.salesforce.com"}, nil
+}
+
+func (s *expiringTokenSource) Refresh(ctx context.Context) (*force.Token, error) {
+	atomic.AddInt32(&s.refreshes, 1)
+	return &force.Token{AccessToken: "renewed", InstanceUrl: "https://example.my.salesforce.com"}, nil
+}
+
+var _ = Describe("Concurrent use", func() {
+	const workers = 20
+
+	var httpClient forcefakes.FakeHttpClient
+
+	BeforeEach(func() {
+		httpClient = forcefakes.FakeHttpClient{}
+	})
+
+	It("should refresh an expired token once", func() {
+		httpClient.DoStub = func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("Authorization") == "Bearer expired" {
+				return NewFakeResponse(`[{"errorCode": "INVALID_SESSION_ID", "message": "Session expired or invalid"}]`, 401), nil
+			}
+			return NewFakeResponse(`{"records": []}`, 200), nil
+		}
+
+		ts := &expiringTokenSource{}
+		forceApi, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithTokenSource(ts),
+			force.WithLazyMetadata(),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		var wg sync.WaitGroup
+		errs := make(chan error, workers)
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- forceApi.Query("SELECT Id FROM Account", &map[string]interface{}{})
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(atomic.LoadInt32(&ts.refreshes)).To(Equal(int32(1)))
+		Expect(forceApi.GetAccessToken()).To(Equal("renewed"))
+	})
+
+	It("should share metadata caches and tracing", func() {
+		httpClient.DoStub = func(req *http.Request) (*http.Response, error) {
+			switch req.URL.Path {
+			case "/services/data/v40.0/sobjects":
+				return NewFakeResponse(`{"sobjects": [{"name": "APIName", "urls": {"describe": "/services/data/v40.0/sobjects/APIName/describe"}}]}`, 200), nil
+			case "/services/data/v40.0/sobjects/APIName/describe":
+				return NewFakeResponse(`{"name": "APIName", "fields": [{"name": "Id"}, {"name": "Name"}]}`, 200), nil
+			}
+			return NewFakeResponse(`{"records": []}`, 200), nil
+		}
+
+		forceApi, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithVersion("v40.0"),
+			force.WithAccessToken("id", "token", "https://example.my.salesforce.com"),
+			force.WithLazyMetadata(),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		logger := log.New(ioutil.Discard, "", 0)
+
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				switch i % 4 {
+				case 0:
+					_, err := forceApi.DescribeSObjects()
+					Expect(err).NotTo(HaveOccurred())
+				case 1:
+					desc, err := forceApi.DescribeSObject(&CompositeSObject{})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(desc.AllFields).To(Equal("Id, Name"))
+				case 2:
+					forceApi.HasAccess([]string{"APIName"})
+					forceApi.SetRetryPolicy(force.DefaultRetryPolicy())
+				case 3:
+					forceApi.TraceOn("race", logger)
+					Expect(forceApi.Query("SELECT Id FROM Account", &map[string]interface{}{})).To(Succeed())
+					forceApi.TraceOff()
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		Expect(forceApi.HasAccess([]string{"APIName"})).To(BeTrue())
+	})
+})
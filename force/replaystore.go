@@ -0,0 +1,124 @@
+package force
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// ReplayStore persists the replay id of the last event processed on each
+// Streaming API channel, so that a subscriber resumes where it stopped after
+// a restart instead of missing or reprocessing events. force.com retains
+// events for three days, after which a stored replay id can't be resumed
+// from anymore.
+type ReplayStore interface {
+	// Load returns the stored replay id of channel, and false when none was
+	// stored yet.
+	Load(ctx context.Context, channel string) (int64, bool, error)
+
+	// Save stores the replay id of channel, replacing the previous one.
+	Save(ctx context.Context, channel string, replayId int64) error
+}
+
+// MemoryReplayStore keeps replay ids in memory.
+type MemoryReplayStore struct {
+	mu        sync.Mutex
+	replayIds map[string]int64
+}
+
+// NewMemoryReplayStore returns an empty MemoryReplayStore.
+func NewMemoryReplayStore() *MemoryReplayStore {
+	return &MemoryReplayStore{replayIds: make(map[string]int64)}
+}
+
+// Load returns the stored replay id of channel, and false when none was
+// stored yet.
+func (s *MemoryReplayStore) Load(ctx context.Context, channel string) (int64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	replayId, ok := s.replayIds[channel]
+	return replayId, ok, nil
+}
+
+// Save stores the replay id of channel, replacing the previous one.
+func (s *MemoryReplayStore) Save(ctx context.Context, channel string, replayId int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.replayIds[channel] = replayId
+	return nil
+}
+
+// FileReplayStore keeps the replay ids of all channels in a json file
+// readable only by its owner. The file is replaced atomically, so it is never
+// left partially written, but it must not be shared between processes.
+type FileReplayStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileReplayStore returns a FileReplayStore keeping its replay ids at
+// path.
+func NewFileReplayStore(path string) *FileReplayStore {
+	return &FileReplayStore{path: path}
+}
+
+// Load returns the stored replay id of channel, and false when none was
+// stored yet.
+func (s *FileReplayStore) Load(ctx context.Context, channel string) (int64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	replayIds, err := s.read()
+	if err != nil {
+		return 0, false, err
+	}
+
+	replayId, ok := replayIds[channel]
+	return replayId, ok, nil
+}
+
+// Save stores the replay id of channel, replacing the previous one.
+func (s *FileReplayStore) Save(ctx context.Context, channel string, replayId int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	replayIds, err := s.read()
+	if err != nil {
+		return err
+	}
+	replayIds[channel] = replayId
+
+	data, err := json.Marshal(replayIds)
+	if err != nil {
+		return fmt.Errorf("Unable to marshal replay ids: %v", err)
+	}
+
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("Unable to write replay file: %v", err)
+	}
+
+	return nil
+}
+
+func (s *FileReplayStore) read() (map[string]int64, error) {
+	replayIds := make(map[string]int64)
+
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return replayIds, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read replay file: %v", err)
+	}
+
+	if err := json.Unmarshal(data, &replayIds); err != nil {
+		return nil, fmt.Errorf("Unable to unmarshal replay file %v: %v", s.path, err)
+	}
+
+	return replayIds, nil
+}
@@ -0,0 +1,45 @@
+package force_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/force"
+)
+
+var _ = Describe("ReplayStore", func() {
+	It("should save replay ids to a file", func() {
+		dir, err := ioutil.TempDir("", "go-force")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		store := force.NewFileReplayStore(filepath.Join(dir, "replay.json"))
+		_, ok, err := store.Load(context.Background(), "/data/AccountChangeEvent")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+
+		Expect(store.Save(context.Background(), "/data/AccountChangeEvent", 10)).To(Succeed())
+		Expect(store.Save(context.Background(), "/data/ContactChangeEvent", 20)).To(Succeed())
+		Expect(store.Save(context.Background(), "/data/AccountChangeEvent", 11)).To(Succeed())
+
+		store = force.NewFileReplayStore(filepath.Join(dir, "replay.json"))
+		replayId, ok, err := store.Load(context.Background(), "/data/AccountChangeEvent")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(replayId).To(Equal(int64(11)))
+
+		replayId, ok, err = store.Load(context.Background(), "/data/ContactChangeEvent")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(replayId).To(Equal(int64(20)))
+
+		info, err := os.Stat(filepath.Join(dir, "replay.json"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+	})
+})
@@ -0,0 +1,118 @@
+package force_test
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/forcefakes"
+)
+
+// createForceApiWithRequestErrors is like createForceApi but builds the
+// ForceApi with force.WithRequestErrors.
+func createForceApiWithRequestErrors(httpClient *forcefakes.FakeHttpClient) (*force.ForceApi, error) {
+	httpClient.DoReturnsOnCall(0, NewFakeResponse(FakeOauthRespBody, 200), nil)
+	httpClient.DoReturnsOnCall(1, NewFakeResponse(`{"sobjects": "sobjects-resources"}`, 200), nil)
+	httpClient.DoReturnsOnCall(2, NewFakeResponse(`{"sobjects": [{"name": "APIName", "urls": {"sobject": "the/url"}}]}`, 200), nil)
+
+	return force.New(
+		force.WithHttpClient(httpClient),
+		force.WithVersion(""),
+		force.WithPassword("", "", "", "", ""),
+		force.WithRequestErrors(),
+	)
+}
+
+var _ = Describe("RequestError", func() {
+	var (
+		httpClient forcefakes.FakeHttpClient
+		forceApi   *force.ForceApi
+	)
+
+	BeforeEach(func() {
+		httpClient = forcefakes.FakeHttpClient{}
+		var err error
+		forceApi, err = createForceApiWithRequestErrors(&httpClient)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should carry the request and response details", func() {
+		body := `[{"errorCode": "NOT_FOUND", "message": "The requested resource does not exist"}]`
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(body, 404), nil)
+
+		err := forceApi.GetSObject("001A", nil, &CompositeSObject{})
+		Expect(err).To(HaveOccurred())
+
+		var reqErr *force.RequestError
+		Expect(errors.As(err, &reqErr)).To(BeTrue())
+		Expect(reqErr.StatusCode).To(Equal(404))
+		Expect(reqErr.Method).To(Equal("GET"))
+		Expect(reqErr.Path).To(Equal("sobjects-resources/APIName/001A"))
+		Expect(string(reqErr.Body)).To(Equal(body))
+		Expect(reqErr.Errors).To(HaveLen(1))
+
+		var apiErrors force.ApiErrors
+		Expect(errors.As(err, &apiErrors)).To(BeTrue())
+		var apiError *force.ApiError
+		Expect(errors.As(err, &apiError)).To(BeTrue())
+		Expect(apiError.ErrorCode).To(Equal("NOT_FOUND"))
+
+		Expect(errors.Is(err, force.ErrNotFound)).To(BeTrue())
+		Expect(errors.Is(err, force.ErrDuplicate)).To(BeFalse())
+		Expect(force.IsNotFound(fmt.Errorf("wrapped: %w", err))).To(BeTrue())
+
+		found, err := force.WasNotFound(err)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+	})
+
+	It("should leave ApiErrors as the error of requests unless asked for", func() {
+		httpClient := forcefakes.FakeHttpClient{}
+		forceApi, err := createForceApi(&httpClient)
+		Expect(err).NotTo(HaveOccurred())
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`[{"errorCode": "DUPLICATE_VALUE", "message": "duplicate"}]`, 400), nil)
+
+		err = forceApi.GetSObject("001A", nil, &CompositeSObject{})
+		apiErrors, ok := err.(force.ApiErrors)
+		Expect(ok).To(BeTrue())
+		Expect(apiErrors[0].ErrorCode).To(Equal("DUPLICATE_VALUE"))
+
+		Expect(errors.Is(err, force.ErrDuplicate)).To(BeTrue())
+		var apiError *force.ApiError
+		Expect(errors.As(err, &apiError)).To(BeTrue())
+		Expect(apiError).To(Equal(apiErrors[0]))
+		var reqErr *force.RequestError
+		Expect(errors.As(err, &reqErr)).To(BeFalse())
+	})
+
+	It("should be returned for responses without api errors", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`Too Many Requests`, 429), nil)
+
+		_, err := forceApi.GetBulkIngestJob("750A")
+		Expect(err).To(HaveOccurred())
+		Expect(force.IsRateLimited(err)).To(BeTrue())
+		Expect(errors.Unwrap(err)).To(BeNil())
+	})
+
+	It("should test the kind of errors", func() {
+		predicates := map[string]func(error) bool{
+			"DUPLICATES_DETECTED":               force.IsDuplicate,
+			"FIELD_CUSTOM_VALIDATION_EXCEPTION": force.IsValidationError,
+			"REQUIRED_FIELD_MISSING":            force.IsValidationError,
+			"UNABLE_TO_LOCK_ROW":                force.IsRowLock,
+			"REQUEST_LIMIT_EXCEEDED":            force.IsRateLimited,
+			"INVALID_SESSION_ID":                force.IsSessionExpired,
+		}
+
+		for code, is := range predicates {
+			Expect(is(force.ApiErrors{{ErrorCode: code}})).To(BeTrue(), code)
+			Expect(is(&force.ApiError{StatusCode: code})).To(BeTrue(), code)
+			Expect(is(force.ApiErrors{{ErrorCode: "OTHER"}})).To(BeFalse(), code)
+			Expect(is(errors.New(code))).To(BeFalse(), code)
+			Expect(is(nil)).To(BeFalse(), code)
+		}
+	})
+})
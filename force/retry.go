@@ -0,0 +1,180 @@
+package force
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/opendoor-labs/go-force/forcejson"
+)
+
+const (
+	DefaultRetryMaxAttempts    = 3
+	DefaultRetryInitialBackoff = 500 * time.Millisecond
+	DefaultRetryMaxBackoff     = 10 * time.Second
+)
+
+// DefaultRetryableErrorCodes are the force.com api error codes retried by a
+// RetryPolicy that doesn't list its own. force.com rejects the request as a
+// whole with these, so retrying them is safe even for inserts.
+var DefaultRetryableErrorCodes = []string{
+	"UNABLE_TO_LOCK_ROW",
+	"REQUEST_LIMIT_EXCEEDED",
+	"SERVER_UNAVAILABLE",
+}
+
+// RetryPolicy controls how requests that failed with a transient error are
+// retried. The zero value of each field picks its default, so
+// &RetryPolicy{} retries like DefaultRetryPolicy().
+//
+// By default a request is retried when force.com answers with one of
+// RetryableErrorCodes, whatever its method. Responses with a 5xx status code
+// and network errors are only retried for idempotent methods, since a POST
+// may have been processed before the failure and retrying it could create
+// duplicate records; set RetryNonIdempotent to retry those too.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, the first included.
+	MaxAttempts int
+
+	// InitialBackoff is the wait before the first retry. It doubles for each
+	// following retry, up to MaxBackoff. Each wait is randomized to between
+	// half and all of its value so that clients don't retry in lockstep.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// RetryableErrorCodes are the force.com api error codes worth retrying.
+	RetryableErrorCodes []string
+
+	// RetryNonIdempotent allows retrying POST requests after 5xx responses
+	// and network errors.
+	RetryNonIdempotent bool
+
+	// Retryable, when set, replaces the rules above to decide whether an
+	// attempt is retried. statusCode is zero and apiErrors nil when no
+	// response was received, in which case err is set.
+	Retryable func(method string, statusCode int, apiErrors ApiErrors, err error) bool
+}
+
+// DefaultRetryPolicy returns a policy making up to 3 attempts, waiting
+// around half a second before the first retry.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:         DefaultRetryMaxAttempts,
+		InitialBackoff:      DefaultRetryInitialBackoff,
+		MaxBackoff:          DefaultRetryMaxBackoff,
+		RetryableErrorCodes: DefaultRetryableErrorCodes,
+	}
+}
+
+// SetRetryPolicy makes this ForceApi retry requests that failed with a
+// transient error according to policy. A nil policy turns retries off, which
+// is the default.
+func (forceApi *ForceApi) SetRetryPolicy(policy *RetryPolicy) {
+	forceApi.mu.Lock()
+	defer forceApi.mu.Unlock()
+
+	forceApi.retryPolicy = policy
+}
+
+// shouldRetry reports whether the given attempt, counting from 1, is followed
+// by another one.
+func (p *RetryPolicy) shouldRetry(ctx context.Context, method string, attempt int, resp *http.Response, body []byte, err error) bool {
+	if p == nil || ctx.Err() != nil {
+		return false
+	}
+
+	maxAttempts := p.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = DefaultRetryMaxAttempts
+	}
+	if attempt >= maxAttempts {
+		return false
+	}
+
+	statusCode := 0
+	var apiErrors ApiErrors
+	if resp != nil {
+		statusCode = resp.StatusCode
+		if statusCode >= http.StatusBadRequest {
+			forcejson.Unmarshal(body, &apiErrors)
+		}
+	}
+
+	if p.Retryable != nil {
+		return p.Retryable(method, statusCode, apiErrors, err)
+	}
+
+	if p.hasRetryableErrorCode(apiErrors) {
+		return true
+	}
+
+	if err == nil && statusCode < http.StatusInternalServerError {
+		return false
+	}
+
+	return p.RetryNonIdempotent || isIdempotent(method)
+}
+
+func (p *RetryPolicy) hasRetryableErrorCode(apiErrors ApiErrors) bool {
+	codes := p.RetryableErrorCodes
+	if codes == nil {
+		codes = DefaultRetryableErrorCodes
+	}
+
+	for _, apiError := range apiErrors {
+		for _, code := range codes {
+			if apiError.ErrorCode == code || apiError.StatusCode == code {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// backoff returns how long to wait after the given attempt, counting from 1.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	wait := p.InitialBackoff
+	if wait == 0 {
+		wait = DefaultRetryInitialBackoff
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff == 0 {
+		maxBackoff = DefaultRetryMaxBackoff
+	}
+
+	for i := 1; i < attempt && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > maxBackoff {
+		wait = maxBackoff
+	}
+
+	half := int64(wait / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// isIdempotent reports whether sending a request twice has the same effect as
+// sending it once. force.com only uses PATCH for updates and upserts, which
+// are.
+func isIdempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "PATCH", "DELETE":
+		return true
+	}
+	return false
+}
+
+// sleepContext waits for d or until ctx is done, whichever comes first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
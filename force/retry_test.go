@@ -0,0 +1,108 @@
+package force_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/forcefakes"
+)
+
+var _ = Describe("RetryPolicy", func() {
+	var httpClient forcefakes.FakeHttpClient
+	var forceApi *force.ForceApi
+
+	BeforeEach(func() {
+		httpClient = forcefakes.FakeHttpClient{}
+
+		var err error
+		forceApi, err = createForceApi(&httpClient)
+		Expect(err).NotTo(HaveOccurred())
+
+		forceApi.SetRetryPolicy(&force.RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     2 * time.Millisecond,
+		})
+	})
+
+	It("should not retry without a policy", func() {
+		forceApi.SetRetryPolicy(nil)
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`[{"errorCode": "SERVER_UNAVAILABLE", "message": "try again"}]`, 503), nil)
+
+		_, err := forceApi.Get("/path", nil, &map[string]string{})
+		Expect(err).To(HaveOccurred())
+		Expect(httpClient.DoCallCount()).To(Equal(4))
+	})
+
+	It("should retry idempotent requests after a 5xx response", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`oops`, 502), nil)
+		httpClient.DoReturnsOnCall(4, NewFakeResponse(`{"Name": "value"}`, 200), nil)
+
+		out := map[string]string{}
+		_, err := forceApi.Get("/path", nil, &out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out["Name"]).To(Equal("value"))
+		Expect(httpClient.DoCallCount()).To(Equal(5))
+	})
+
+	It("should retry idempotent requests after a network error", func() {
+		httpClient.DoReturnsOnCall(3, nil, errors.New("connection reset by peer"))
+		httpClient.DoReturnsOnCall(4, NewFakeResponse(``, 204), nil)
+
+		err := forceApi.Delete("/path", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(httpClient.DoCallCount()).To(Equal(5))
+	})
+
+	It("should not retry a POST that may have been processed", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`[{"errorCode": "UNKNOWN_EXCEPTION", "message": "An unexpected error occurred"}]`, 500), nil)
+
+		err := forceApi.Post("/path", nil, map[string]string{"Name": "value"}, nil)
+		Expect(err).To(HaveOccurred())
+		Expect(httpClient.DoCallCount()).To(Equal(4))
+	})
+
+	It("should retry a POST that was rejected with a retryable error code", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`[{"errorCode": "UNABLE_TO_LOCK_ROW", "message": "unable to obtain exclusive access to this record"}]`, 400), nil)
+		httpClient.DoReturnsOnCall(4, NewFakeResponse(`{"id": "001A", "success": true, "errors": []}`, 201), nil)
+
+		resp := &force.SObjectResponse{}
+		err := forceApi.Post("/path", nil, map[string]string{"Name": "value"}, resp)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Id).To(Equal("001A"))
+
+		Expect(httpClient.DoCallCount()).To(Equal(5))
+		body, _ := ioutil.ReadAll(httpClient.DoArgsForCall(4).Body)
+		Expect(body).To(MatchJSON(`{"Name": "value"}`))
+	})
+
+	It("should give up after the maximum number of attempts", func() {
+		for i := 3; i < 6; i++ {
+			httpClient.DoReturnsOnCall(i, NewFakeResponse(`[{"errorCode": "UNABLE_TO_LOCK_ROW", "message": "locked"}]`, 400), nil)
+		}
+
+		_, err := forceApi.Get("/path", nil, &map[string]string{})
+		Expect(force.IsRowLock(err)).To(BeTrue())
+		Expect(httpClient.DoCallCount()).To(Equal(6))
+	})
+
+	It("should let a predicate decide what is retried", func() {
+		forceApi.SetRetryPolicy(&force.RetryPolicy{
+			InitialBackoff: time.Millisecond,
+			Retryable: func(method string, statusCode int, apiErrors force.ApiErrors, err error) bool {
+				return statusCode == 429
+			},
+		})
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`[]`, 429), nil)
+		httpClient.DoReturnsOnCall(4, NewFakeResponse(`oops`, 503), nil)
+
+		_, err := forceApi.Get("/path", nil, &map[string]string{})
+		Expect(err).To(HaveOccurred())
+		Expect(httpClient.DoCallCount()).To(Equal(5))
+	})
+})
@@ -0,0 +1,224 @@
+package force
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/opendoor-labs/go-force/forcejson"
+)
+
+const (
+	searchKey              = "search"
+	parameterizedSearchKey = "parameterizedSearch"
+)
+
+// Scopes of the fields a parameterized search looks into.
+const (
+	SearchInAll     = "ALL"
+	SearchInName    = "NAME"
+	SearchInEmail   = "EMAIL"
+	SearchInPhone   = "PHONE"
+	SearchInSidebar = "SIDEBAR"
+)
+
+type searchResponse struct {
+	SearchRecords []*forcejson.RawMessage `force:"searchRecords"`
+}
+
+type searchRecordAttributes struct {
+	Attributes struct {
+		Type string `force:"type"`
+	} `force:"attributes"`
+}
+
+// Search executes a SOSL search and unmarshals the matched records into out,
+// grouped by sobject type in order of relevance. out is a pointer to a struct
+// whose slice fields are named after the types returned, through their force
+// tag or their name, or a pointer to a map of slices keyed by type:
+//
+//	var result struct {
+//		Accounts []*sobjects.Account `force:"Account"`
+//		Contacts []*Contact          `force:"Contact"`
+//	}
+//	err := forceApi.Search("FIND {Acme} RETURNING Account(Id, Name), Contact(Id, Name)", &result)
+//
+// Records of types out has no field for are left out.
+func (forceApi *ForceApi) Search(sosl string, out interface{}) error {
+	return forceApi.SearchContext(context.Background(), sosl, out)
+}
+
+// SearchContext is like Search but carries ctx to the underlying http request.
+func (forceApi *ForceApi) SearchContext(ctx context.Context, sosl string, out interface{}) error {
+	uri := forceApi.resourceUrl(searchKey)
+
+	params := url.Values{
+		"q": {sosl},
+	}
+
+	_, respBytes, err := forceApi.requestRaw(ctx, "GET", uri, params, contentType, responseType, nil)
+	if err != nil {
+		return err
+	}
+
+	return unmarshalSearchRecords(respBytes, out)
+}
+
+// SearchSObject restricts a parameterized search to a sobject type, and
+// tells which of its fields to return.
+type SearchSObject struct {
+	Name    string   `force:"name"`
+	Fields  []string `force:"fields,omitempty"`
+	Where   string   `force:"where,omitempty"`
+	OrderBy string   `force:"orderBy,omitempty"`
+	Limit   int      `force:"limit,omitempty"`
+}
+
+// ParameterizedSearch builds a search executed with the parameterized search
+// resource, which takes the search terms and its options as parameters
+// instead of a SOSL statement.
+//
+//	search := forceApi.NewParameterizedSearch("Acme")
+//	search.AddSObject(force.SearchSObject{Name: "Account", Fields: []string{"Id", "Name"}, Limit: 10})
+//	search.AddSObject(force.SearchSObject{Name: "Contact", Fields: []string{"Id", "Name"}, Where: "MailingCity = 'Paris'"})
+//	search.SetIn(force.SearchInName)
+//	err := search.Send(&result)
+type ParameterizedSearch struct {
+	forceApi *ForceApi
+	body     parameterizedSearchBody
+}
+
+type parameterizedSearchBody struct {
+	Q               string           `force:"q"`
+	Fields          []string         `force:"fields,omitempty"`
+	SObjects        []*SearchSObject `force:"sobjects,omitempty"`
+	In              string           `force:"in,omitempty"`
+	OverallLimit    int              `force:"overallLimit,omitempty"`
+	DefaultLimit    int              `force:"defaultLimit,omitempty"`
+	Offset          int              `force:"offset,omitempty"`
+	Snippet         *searchSnippet   `force:"snippet,omitempty"`
+	SpellCorrection *bool            `force:"spellCorrection,omitempty"`
+}
+
+type searchSnippet struct {
+	TargetLength int `force:"targetLength"`
+}
+
+// NewParameterizedSearch starts a search for the terms of q, such as
+// "Acme" or "Acme OR Initech", in all searchable sobject types.
+func (forceApi *ForceApi) NewParameterizedSearch(q string) *ParameterizedSearch {
+	return &ParameterizedSearch{forceApi: forceApi, body: parameterizedSearchBody{Q: q}}
+}
+
+// AddSObject restricts the search to the given sobject type, along with the
+// ones added before.
+func (s *ParameterizedSearch) AddSObject(sObject SearchSObject) {
+	s.body.SObjects = append(s.body.SObjects, &sObject)
+}
+
+// SetFields sets the fields returned for the sobject types added without
+// fields of their own.
+func (s *ParameterizedSearch) SetFields(fields ...string) {
+	s.body.Fields = fields
+}
+
+// SetIn sets the scope of the fields searched, such as SearchInName. It
+// defaults to SearchInAll.
+func (s *ParameterizedSearch) SetIn(scope string) {
+	s.body.In = scope
+}
+
+// SetOverallLimit sets the most records returned, all types included.
+func (s *ParameterizedSearch) SetOverallLimit(limit int) {
+	s.body.OverallLimit = limit
+}
+
+// SetDefaultLimit sets the most records returned for each sobject type added
+// without a limit of its own.
+func (s *ParameterizedSearch) SetDefaultLimit(limit int) {
+	s.body.DefaultLimit = limit
+}
+
+// SetOffset skips the given number of records, to page through results.
+func (s *ParameterizedSearch) SetOffset(offset int) {
+	s.body.Offset = offset
+}
+
+// SetSnippet asks for a snippet of about targetLength characters around the
+// matched terms, with the matched terms highlighted. Records then hold a
+// "snippet" object with a "text" field, and a "highlight" object with the
+// highlighted fields. Snippets are only returned for some types, such as
+// knowledge articles.
+func (s *ParameterizedSearch) SetSnippet(targetLength int) {
+	s.body.Snippet = &searchSnippet{TargetLength: targetLength}
+}
+
+// SetSpellCorrection turns the correction of misspelled search terms on or
+// off. force.com corrects them by default.
+func (s *ParameterizedSearch) SetSpellCorrection(enabled bool) {
+	s.body.SpellCorrection = &enabled
+}
+
+// Send executes the search and unmarshals the matched records into out,
+// grouped by sobject type as described for Search.
+func (s *ParameterizedSearch) Send(out interface{}) error {
+	return s.SendContext(context.Background(), out)
+}
+
+// SendContext is like Send but carries ctx to the underlying http request.
+func (s *ParameterizedSearch) SendContext(ctx context.Context, out interface{}) error {
+	uri := s.forceApi.resourceUrl(parameterizedSearchKey)
+
+	body, err := forcejson.Marshal(&s.body)
+	if err != nil {
+		return fmt.Errorf("Error marshaling encoded payload: %v", err)
+	}
+
+	_, respBytes, err := s.forceApi.requestRaw(ctx, "POST", uri, nil, contentType, responseType, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	return unmarshalSearchRecords(respBytes, out)
+}
+
+// unmarshalSearchRecords groups the records of a search response by type
+// and unmarshals them into out.
+func unmarshalSearchRecords(respBytes []byte, out interface{}) error {
+	var records []*forcejson.RawMessage
+	if bytes.HasPrefix(bytes.TrimSpace(respBytes), []byte("[")) {
+		// Versions before 37.0 return the records alone.
+		if err := forcejson.Unmarshal(respBytes, &records); err != nil {
+			return fmt.Errorf("Unable to unmarshal search response: %v", err)
+		}
+	} else {
+		resp := &searchResponse{}
+		if err := forcejson.Unmarshal(respBytes, resp); err != nil {
+			return fmt.Errorf("Unable to unmarshal search response: %v", err)
+		}
+		records = resp.SearchRecords
+	}
+
+	byType := make(map[string][]*forcejson.RawMessage)
+	for _, record := range records {
+		attributes := &searchRecordAttributes{}
+		if err := forcejson.Unmarshal(*record, attributes); err != nil {
+			return fmt.Errorf("Unable to unmarshal search record: %v", err)
+		}
+
+		sObjectType := attributes.Attributes.Type
+		byType[sObjectType] = append(byType[sObjectType], record)
+	}
+
+	grouped, err := forcejson.Marshal(byType)
+	if err != nil {
+		return fmt.Errorf("Unable to unmarshal search response: %v", err)
+	}
+
+	if err := forcejson.Unmarshal(grouped, out); err != nil {
+		return fmt.Errorf("Unable to unmarshal search records: %v", err)
+	}
+
+	return nil
+}
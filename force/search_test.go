@@ -0,0 +1,123 @@
+package force_test
+
+import (
+	"io/ioutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/forcefakes"
+)
+
+var _ = Describe("Search", func() {
+	var httpClient forcefakes.FakeHttpClient
+	var forceApi *force.ForceApi
+
+	const searchResp = `{"searchRecords": [
+		{"attributes": {"type": "Account", "url": "/services/data/v52.0/sobjects/Account/001A"}, "Id": "001A", "Name": "Acme"},
+		{"attributes": {"type": "Contact", "url": "/services/data/v52.0/sobjects/Contact/003A"}, "Id": "003A", "Name": "Wile E.", "AccountId": "001A"},
+		{"attributes": {"type": "Account", "url": "/services/data/v52.0/sobjects/Account/001B"}, "Id": "001B", "Name": "Acme Labs"}
+	]}`
+
+	type searchResult struct {
+		Accounts []*CompositeSObject `force:"Account"`
+		Contacts []*CompositeSObject `force:"Contact"`
+	}
+
+	BeforeEach(func() {
+		httpClient = forcefakes.FakeHttpClient{}
+
+		var err error
+		forceApi, err = createForceApi(&httpClient)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should group search records by type", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(searchResp, 200), nil)
+
+		result := &searchResult{}
+		err := forceApi.Search("FIND {Acme} RETURNING Account(Id, Name), Contact(Id, Name, AccountId)", result)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(&searchResult{
+			Accounts: []*CompositeSObject{{Id: "001A", Name: "Acme"}, {Id: "001B", Name: "Acme Labs"}},
+			Contacts: []*CompositeSObject{{Id: "003A", Name: "Wile E.", AccountId: "001A"}},
+		}))
+
+		req := httpClient.DoArgsForCall(3)
+		Expect(req.Method).To(Equal("GET"))
+		Expect(req.URL.Path).To(HaveSuffix("/search"))
+		Expect(req.URL.Query().Get("q")).To(Equal("FIND {Acme} RETURNING Account(Id, Name), Contact(Id, Name, AccountId)"))
+	})
+
+	It("should decode search records into a map", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(searchResp, 200), nil)
+
+		result := map[string][]*CompositeSObject{}
+		err := forceApi.Search("FIND {Acme}", &result)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(HaveLen(2))
+		Expect(result["Account"]).To(HaveLen(2))
+		Expect(result["Contact"]).To(Equal([]*CompositeSObject{{Id: "003A", Name: "Wile E.", AccountId: "001A"}}))
+	})
+
+	It("should decode search records returned alone", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`[
+			{"attributes": {"type": "Account"}, "Id": "001A", "Name": "Acme"}
+		]`, 200), nil)
+
+		result := &searchResult{}
+		err := forceApi.Search("FIND {Acme}", result)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Accounts).To(Equal([]*CompositeSObject{{Id: "001A", Name: "Acme"}}))
+		Expect(result.Contacts).To(BeEmpty())
+	})
+
+	It("should return search errors", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`[{"errorCode": "MALFORMED_SEARCH", "message": "No search term found."}]`, 400), nil)
+
+		err := forceApi.Search("FIND {}", &searchResult{})
+		Expect(err).To(MatchError(ContainSubstring("MALFORMED_SEARCH")))
+	})
+
+	It("should send parameterized searches", func() {
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(searchResp, 200), nil)
+
+		search := forceApi.NewParameterizedSearch("Acme")
+		search.SetFields("Id", "Name")
+		search.AddSObject(force.SearchSObject{Name: "Account", Limit: 10})
+		search.AddSObject(force.SearchSObject{
+			Name:    "Contact",
+			Fields:  []string{"Id", "Name", "AccountId"},
+			Where:   "MailingCity = 'Paris'",
+			OrderBy: "Name",
+		})
+		search.SetIn(force.SearchInName)
+		search.SetOverallLimit(50)
+		search.SetSnippet(120)
+		search.SetSpellCorrection(false)
+
+		result := &searchResult{}
+		err := search.Send(result)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Accounts).To(HaveLen(2))
+		Expect(result.Contacts).To(HaveLen(1))
+
+		req := httpClient.DoArgsForCall(3)
+		Expect(req.Method).To(Equal("POST"))
+		Expect(req.URL.Path).To(HaveSuffix("/parameterizedSearch"))
+		body, _ := ioutil.ReadAll(req.Body)
+		Expect(body).To(MatchJSON(`{
+			"q": "Acme",
+			"fields": ["Id", "Name"],
+			"sobjects": [
+				{"name": "Account", "limit": 10},
+				{"name": "Contact", "fields": ["Id", "Name", "AccountId"], "where": "MailingCity = 'Paris'", "orderBy": "Name"}
+			],
+			"in": "NAME",
+			"overallLimit": 50,
+			"snippet": {"targetLength": 120},
+			"spellCorrection": false
+		}`))
+	})
+})
@@ -0,0 +1,124 @@
+package force
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// TokenIntrospection describes an access or refresh token, as returned by
+// IntrospectToken.
+type TokenIntrospection struct {
+	// Active is false when the token expired, was revoked or is unknown.
+	// The other fields are only set for active tokens.
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientId  string `json:"client_id,omitempty"`
+	UserName  string `json:"username,omitempty"`
+	Sub       string `json:"sub,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Aud       string `json:"aud,omitempty"`
+	Iss       string `json:"iss,omitempty"`
+
+	// Exp, Iat and Nbf are unix times.
+	Exp int64 `json:"exp,omitempty"`
+	Iat int64 `json:"iat,omitempty"`
+	Nbf int64 `json:"nbf,omitempty"`
+}
+
+// UserInfo is the identity of the user a ForceApi acts as, as returned by
+// the OpenID Connect userinfo endpoint.
+type UserInfo struct {
+	Sub               string            `json:"sub" force:"sub"`
+	UserId            string            `json:"user_id" force:"user_id"`
+	OrganizationId    string            `json:"organization_id" force:"organization_id"`
+	PreferredUsername string            `json:"preferred_username" force:"preferred_username"`
+	Nickname          string            `json:"nickname" force:"nickname"`
+	Name              string            `json:"name" force:"name"`
+	GivenName         string            `json:"given_name" force:"given_name"`
+	FamilyName        string            `json:"family_name" force:"family_name"`
+	Email             string            `json:"email" force:"email"`
+	EmailVerified     bool              `json:"email_verified" force:"email_verified"`
+	ZoneInfo          string            `json:"zoneinfo" force:"zoneinfo"`
+	Locale            string            `json:"locale" force:"locale"`
+	Language          string            `json:"language" force:"language"`
+	UtcOffset         int64             `json:"utcOffset" force:"utcOffset"`
+	UserType          string            `json:"user_type" force:"user_type"`
+	Active            bool              `json:"active" force:"active"`
+	Profile           string            `json:"profile" force:"profile"`
+	Picture           string            `json:"picture" force:"picture"`
+	UpdatedAt         string            `json:"updated_at" force:"updated_at"`
+	Urls              map[string]string `json:"urls" force:"urls"`
+}
+
+// RevokeToken revokes token, an access or refresh token issued to this
+// ForceApi's connected app. Revoking a refresh token also revokes the access
+// tokens obtained with it. Pass GetAccessToken() to log out.
+func (forceApi *ForceApi) RevokeToken(token string) error {
+	return forceApi.RevokeTokenContext(context.Background(), token)
+}
+
+// RevokeTokenContext is like RevokeToken but carries ctx to the underlying http request.
+func (forceApi *ForceApi) RevokeTokenContext(ctx context.Context, token string) error {
+	payload := url.Values{
+		"token": {token},
+	}
+
+	_, err := forceApi.oauth.postForm(ctx, revokePath, payload)
+	return err
+}
+
+// IntrospectToken describes token, an access or refresh token. tokenTypeHint,
+// either "access_token" or "refresh_token", may be empty. force.com requires
+// the connected app's credentials, so only a ForceApi built with one of
+// WithPassword, WithRefreshToken and WithClientCredentials can introspect
+// tokens.
+func (forceApi *ForceApi) IntrospectToken(token, tokenTypeHint string) (*TokenIntrospection, error) {
+	return forceApi.IntrospectTokenContext(context.Background(), token, tokenTypeHint)
+}
+
+// IntrospectTokenContext is like IntrospectToken but carries ctx to the underlying http request.
+func (forceApi *ForceApi) IntrospectTokenContext(ctx context.Context, token, tokenTypeHint string) (*TokenIntrospection, error) {
+	if forceApi.oauth.clientId == "" {
+		return nil, fmt.Errorf("Unable to introspect token: no connected app credentials given")
+	}
+
+	payload := url.Values{
+		"token":     {token},
+		"client_id": {forceApi.oauth.clientId},
+	}
+	if forceApi.oauth.clientSecret != "" {
+		payload.Set("client_secret", forceApi.oauth.clientSecret)
+	}
+	if tokenTypeHint != "" {
+		payload.Set("token_type_hint", tokenTypeHint)
+	}
+
+	respBytes, err := forceApi.oauth.postForm(ctx, introspectPath, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	introspection := &TokenIntrospection{}
+	if err := json.Unmarshal(respBytes, introspection); err != nil {
+		return nil, fmt.Errorf("Unable to unmarshal introspection response: %v", err)
+	}
+
+	return introspection, nil
+}
+
+// UserInfo returns the identity of the user this ForceApi acts as.
+func (forceApi *ForceApi) UserInfo() (*UserInfo, error) {
+	return forceApi.UserInfoContext(context.Background())
+}
+
+// UserInfoContext is like UserInfo but carries ctx to the underlying http request.
+func (forceApi *ForceApi) UserInfoContext(ctx context.Context) (*UserInfo, error) {
+	userInfo := &UserInfo{}
+	if _, err := forceApi.GetContext(ctx, userInfoPath, nil, userInfo); err != nil {
+		return nil, err
+	}
+
+	return userInfo, nil
+}
@@ -0,0 +1,98 @@
+package force_test
+
+import (
+	"io/ioutil"
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/forcefakes"
+)
+
+var _ = Describe("Session", func() {
+	var (
+		httpClient forcefakes.FakeHttpClient
+		forceApi   *force.ForceApi
+	)
+
+	BeforeEach(func() {
+		httpClient = forcefakes.FakeHttpClient{}
+		httpClient.DoReturnsOnCall(0, NewFakeResponse(`{"access_token": "at", "instance_url": "https://example.my.salesforce.com"}`, 200), nil)
+
+		var err error
+		forceApi, err = force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithPassword("id", "secret", "user", "pass", "token"),
+			force.WithLoginUrl("https://example.my.salesforce.com"),
+			force.WithLazyMetadata(),
+		)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should revoke a token", func() {
+		httpClient.DoReturnsOnCall(1, NewFakeResponse(``, 200), nil)
+
+		Expect(forceApi.RevokeToken(forceApi.GetAccessToken())).To(Succeed())
+
+		req := httpClient.DoArgsForCall(1)
+		Expect(req.Method).To(Equal("POST"))
+		Expect(req.URL.String()).To(Equal("https://example.my.salesforce.com/services/oauth2/revoke"))
+		body, _ := ioutil.ReadAll(req.Body)
+		form, _ := url.ParseQuery(string(body))
+		Expect(form).To(Equal(url.Values{"token": {"at"}}))
+	})
+
+	It("should return revocation errors", func() {
+		httpClient.DoReturnsOnCall(1, NewFakeResponse(`{"error": "unsupported_token_type", "error_description": "this token type is not supported"}`, 400), nil)
+
+		err := forceApi.RevokeToken("unknown")
+		Expect(err).To(HaveOccurred())
+		Expect(err.(*force.ApiError).ErrorName).To(Equal("unsupported_token_type"))
+	})
+
+	It("should introspect a token", func() {
+		httpClient.DoReturnsOnCall(1, NewFakeResponse(`{"active": true, "scope": "api refresh_token", "client_id": "id", "username": "user@example.com", "token_type": "access_token", "exp": 1700000000}`, 200), nil)
+
+		introspection, err := forceApi.IntrospectToken("at", "access_token")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(introspection).To(Equal(&force.TokenIntrospection{
+			Active:    true,
+			Scope:     "api refresh_token",
+			ClientId:  "id",
+			UserName:  "user@example.com",
+			TokenType: "access_token",
+			Exp:       1700000000,
+		}))
+
+		req := httpClient.DoArgsForCall(1)
+		Expect(req.URL.String()).To(Equal("https://example.my.salesforce.com/services/oauth2/introspect"))
+		body, _ := ioutil.ReadAll(req.Body)
+		form, _ := url.ParseQuery(string(body))
+		Expect(form).To(Equal(url.Values{
+			"token":           {"at"},
+			"token_type_hint": {"access_token"},
+			"client_id":       {"id"},
+			"client_secret":   {"secret"},
+		}))
+	})
+
+	It("should return the user info", func() {
+		httpClient.DoReturnsOnCall(1, NewFakeResponse(`{"sub": "https://login.salesforce.com/id/00D/005", "user_id": "005", "organization_id": "00D", "preferred_username": "user@example.com", "email_verified": true, "utcOffset": -28800000, "urls": {"rest": "https://example.my.salesforce.com/services/data/v{version}/"}}`, 200), nil)
+
+		userInfo, err := forceApi.UserInfo()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(userInfo.UserId).To(Equal("005"))
+		Expect(userInfo.OrganizationId).To(Equal("00D"))
+		Expect(userInfo.PreferredUsername).To(Equal("user@example.com"))
+		Expect(userInfo.EmailVerified).To(BeTrue())
+		Expect(userInfo.UtcOffset).To(Equal(int64(-28800000)))
+		Expect(userInfo.Urls).To(HaveKeyWithValue("rest", "https://example.my.salesforce.com/services/data/v{version}/"))
+
+		req := httpClient.DoArgsForCall(1)
+		Expect(req.Method).To(Equal("GET"))
+		Expect(req.URL.String()).To(Equal("https://example.my.salesforce.com/services/oauth2/userinfo"))
+		Expect(req.Header.Get("Authorization")).To(Equal("Bearer at"))
+	})
+})
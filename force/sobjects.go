@@ -2,6 +2,7 @@ package force
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -22,33 +23,50 @@ type SObject interface {
 
 // Response received from force.com API after insert of an sobject.
 type SObjectResponse struct {
-	Id     string    `force:"id,omitempty"`
-	Errors ApiErrors `force:"error,omitempty"` //TODO: Not sure if ApiErrors is the right object
+	Id      string    `force:"id,omitempty"`
+	Success bool      `force:"success,omitempty"`
+	Created bool      `force:"created,omitempty"`
+	Errors  ApiErrors `force:"errors,omitempty"`
 }
 
 func (forceAPI *ForceApi) DescribeSObjects() (map[string]*SObjectMetaData, error) {
-	if err := forceAPI.getApiSObjects(); err != nil {
+	return forceAPI.DescribeSObjectsContext(context.Background())
+}
+
+// DescribeSObjectsContext is like DescribeSObjects but carries ctx to the underlying http request.
+func (forceAPI *ForceApi) DescribeSObjectsContext(ctx context.Context) (map[string]*SObjectMetaData, error) {
+	if err := forceAPI.getApiSObjects(ctx); err != nil {
 		return nil, err
 	}
 
-	return forceAPI.apiSObjects, nil
+	return forceAPI.sObjects(), nil
 }
 
 func (forceApi *ForceApi) DescribeSObject(in SObject) (resp *SObjectDescription, err error) {
+	return forceApi.DescribeSObjectContext(context.Background(), in)
+}
+
+// DescribeSObjectContext is like DescribeSObject but carries ctx to the underlying http request.
+func (forceApi *ForceApi) DescribeSObjectContext(ctx context.Context, in SObject) (resp *SObjectDescription, err error) {
 	// Check cache
+	forceApi.mu.RLock()
 	resp, ok := forceApi.apiSObjectDescriptions[in.APIName()]
+	_, hasMetaData := forceApi.apiSObjects[in.APIName()]
+	hasSObjects := len(forceApi.apiSObjects) != 0
+	forceApi.mu.RUnlock()
+
 	if !ok {
-		// Attempt retrieval from api
-		sObjectMetaData, ok := forceApi.apiSObjects[in.APIName()]
-		if !ok {
+		// Attempt retrieval from api. Without metadata, as with lazy
+		// loading, the conventional url is tried.
+		if !hasMetaData && hasSObjects {
 			err = fmt.Errorf("Unable to find metadata for object: %v", in.APIName())
 			return
 		}
 
-		uri := sObjectMetaData.URLs[sObjectDescribeKey]
+		uri := forceApi.sObjectUrl(in.APIName(), sObjectDescribeKey)
 
 		resp = &SObjectDescription{}
-		_, err = forceApi.Get(uri, nil, resp)
+		_, err = forceApi.GetContext(ctx, uri, nil, resp)
 		if err != nil {
 			return
 		}
@@ -71,46 +89,68 @@ func (forceApi *ForceApi) DescribeSObject(in SObject) (resp *SObjectDescription,
 			resp.AllFields = allFields.String()
 		}
 
+		forceApi.mu.Lock()
 		forceApi.apiSObjectDescriptions[in.APIName()] = resp
+		forceApi.mu.Unlock()
 	}
 
 	return
 }
 
 func (forceApi *ForceApi) GetSObject(id string, fields []string, out SObject) (err error) {
-	uri := strings.Replace(forceApi.apiSObjects[out.APIName()].URLs[rowTemplateKey], idKey, id, 1)
+	return forceApi.GetSObjectContext(context.Background(), id, fields, out)
+}
+
+// GetSObjectContext is like GetSObject but carries ctx to the underlying http request.
+func (forceApi *ForceApi) GetSObjectContext(ctx context.Context, id string, fields []string, out SObject) (err error) {
+	uri := strings.Replace(forceApi.sObjectUrl(out.APIName(), rowTemplateKey), idKey, id, 1)
 
 	params := url.Values{}
 	if len(fields) > 0 {
 		params.Add("fields", strings.Join(fields, ","))
 	}
 
-	_, err = forceApi.Get(uri, params, out.(interface{}))
+	_, err = forceApi.GetContext(ctx, uri, params, out.(interface{}))
 
 	return
 }
 
 func (forceApi *ForceApi) InsertSObject(in SObject) (resp *SObjectResponse, err error) {
-	uri := forceApi.apiSObjects[in.APIName()].URLs[sObjectKey]
+	return forceApi.InsertSObjectContext(context.Background(), in)
+}
+
+// InsertSObjectContext is like InsertSObject but carries ctx to the underlying http request.
+func (forceApi *ForceApi) InsertSObjectContext(ctx context.Context, in SObject) (resp *SObjectResponse, err error) {
+	uri := forceApi.sObjectUrl(in.APIName(), sObjectKey)
 
 	resp = &SObjectResponse{}
-	err = forceApi.Post(uri, nil, in.(interface{}), resp)
+	err = forceApi.PostContext(ctx, uri, nil, in.(interface{}), resp)
 
 	return
 }
 
 func (forceApi *ForceApi) UpdateSObject(id string, in SObject) (err error) {
-	uri := strings.Replace(forceApi.apiSObjects[in.APIName()].URLs[rowTemplateKey], idKey, id, 1)
+	return forceApi.UpdateSObjectContext(context.Background(), id, in)
+}
+
+// UpdateSObjectContext is like UpdateSObject but carries ctx to the underlying http request.
+func (forceApi *ForceApi) UpdateSObjectContext(ctx context.Context, id string, in SObject) (err error) {
+	uri := strings.Replace(forceApi.sObjectUrl(in.APIName(), rowTemplateKey), idKey, id, 1)
 
-	_, err = forceApi.Patch(uri, nil, in.(interface{}), nil)
+	_, err = forceApi.PatchContext(ctx, uri, nil, in.(interface{}), nil)
 
 	return
 }
 
 func (forceApi *ForceApi) DeleteSObject(id string, in SObject) (err error) {
-	uri := strings.Replace(forceApi.apiSObjects[in.APIName()].URLs[rowTemplateKey], idKey, id, 1)
+	return forceApi.DeleteSObjectContext(context.Background(), id, in)
+}
 
-	err = forceApi.Delete(uri, nil)
+// DeleteSObjectContext is like DeleteSObject but carries ctx to the underlying http request.
+func (forceApi *ForceApi) DeleteSObjectContext(ctx context.Context, id string, in SObject) (err error) {
+	uri := strings.Replace(forceApi.sObjectUrl(in.APIName(), rowTemplateKey), idKey, id, 1)
+
+	err = forceApi.DeleteContext(ctx, uri, nil)
 
 	return
 }
@@ -124,18 +164,18 @@ func idsFromURIs(uris []string) []string {
 	return ids
 }
 
-func (forceApi *ForceApi) getSingleSFID(uri string, params url.Values) (string, int, error) {
+func (forceApi *ForceApi) getSingleSFID(ctx context.Context, uri string, params url.Values) (string, int, error) {
 	sobj := sobjects.BaseSObject{}
-	statusCode, err := forceApi.Get(uri, params, &sobj)
+	statusCode, err := forceApi.GetContext(ctx, uri, params, &sobj)
 	return sobj.Id, statusCode, err
 }
 
-func (forceApi *ForceApi) getMultipleSFIDs(uri string, params url.Values) ([]string, int, error) {
+func (forceApi *ForceApi) getMultipleSFIDs(ctx context.Context, uri string, params url.Values) ([]string, int, error) {
 	// We don't have access to the json that was returned, so make the
 	// same call as was made in getSingleSFID() passing in a slice to
 	// unmarshal into.
 	uris := []string{}
-	statusCode, err := forceApi.Get(uri, params, &uris)
+	statusCode, err := forceApi.GetContext(ctx, uri, params, &uris)
 	if err != nil {
 		return nil, statusCode, err
 	}
@@ -144,10 +184,15 @@ func (forceApi *ForceApi) getMultipleSFIDs(uri string, params url.Values) ([]str
 }
 
 func (forceApi *ForceApi) GetSFIDsByExternalId(apiName, externalKey, externalId string) ([]string, int, error) {
-	uri := fmt.Sprintf("%v/%v/%v", forceApi.apiSObjects[apiName].URLs[sObjectKey], externalKey, externalId)
+	return forceApi.GetSFIDsByExternalIdContext(context.Background(), apiName, externalKey, externalId)
+}
+
+// GetSFIDsByExternalIdContext is like GetSFIDsByExternalId but carries ctx to the underlying http request.
+func (forceApi *ForceApi) GetSFIDsByExternalIdContext(ctx context.Context, apiName, externalKey, externalId string) ([]string, int, error) {
+	uri := fmt.Sprintf("%v/%v/%v", forceApi.sObjectUrl(apiName, sObjectKey), externalKey, externalId)
 	params := url.Values{"fields": []string{"Id"}}
 
-	sfid, statusCode, err := forceApi.getSingleSFID(uri, params)
+	sfid, statusCode, err := forceApi.getSingleSFID(ctx, uri, params)
 	if err == nil {
 		return []string{sfid}, statusCode, nil
 	}
@@ -157,14 +202,19 @@ func (forceApi *ForceApi) GetSFIDsByExternalId(apiName, externalKey, externalId
 	// ID exists in more than one record. The response body contains the
 	// list of matching records.
 	if statusCode == http.StatusMultipleChoices {
-		return forceApi.getMultipleSFIDs(uri, params)
+		return forceApi.getMultipleSFIDs(ctx, uri, params)
 	}
 
 	return nil, statusCode, err
 }
 
 func (forceApi *ForceApi) GetSObjectByExternalId(externalKey, externalId string, fields []string, out SObject) (statusCode int, err error) {
-	uri := fmt.Sprintf("%v/%v/%v", forceApi.apiSObjects[out.APIName()].URLs[sObjectKey],
+	return forceApi.GetSObjectByExternalIdContext(context.Background(), externalKey, externalId, fields, out)
+}
+
+// GetSObjectByExternalIdContext is like GetSObjectByExternalId but carries ctx to the underlying http request.
+func (forceApi *ForceApi) GetSObjectByExternalIdContext(ctx context.Context, externalKey, externalId string, fields []string, out SObject) (statusCode int, err error) {
+	uri := fmt.Sprintf("%v/%v/%v", forceApi.sObjectUrl(out.APIName(), sObjectKey),
 		externalKey, externalId)
 
 	params := url.Values{}
@@ -172,25 +222,37 @@ func (forceApi *ForceApi) GetSObjectByExternalId(externalKey, externalId string,
 		params.Add("fields", strings.Join(fields, ","))
 	}
 
-	return forceApi.Get(uri, params, out.(interface{}))
+	return forceApi.GetContext(ctx, uri, params, out.(interface{}))
 }
 
 func (forceApi *ForceApi) UpsertSObjectByExternalId(
 	externalKey string, externalId string, in SObject) (responseCode int, resp *SObjectResponse, err error) {
 
-	uri := fmt.Sprintf("%v/%v/%v", forceApi.apiSObjects[in.APIName()].URLs[sObjectKey], externalKey, externalId)
+	return forceApi.UpsertSObjectByExternalIdContext(context.Background(), externalKey, externalId, in)
+}
+
+// UpsertSObjectByExternalIdContext is like UpsertSObjectByExternalId but carries ctx to the underlying http request.
+func (forceApi *ForceApi) UpsertSObjectByExternalIdContext(ctx context.Context,
+	externalKey string, externalId string, in SObject) (responseCode int, resp *SObjectResponse, err error) {
+
+	uri := fmt.Sprintf("%v/%v/%v", forceApi.sObjectUrl(in.APIName(), sObjectKey), externalKey, externalId)
 
 	resp = &SObjectResponse{}
-	responseCode, err = forceApi.Patch(uri, nil, in.(interface{}), resp)
+	responseCode, err = forceApi.PatchContext(ctx, uri, nil, in.(interface{}), resp)
 
 	return
 }
 
 func (forceApi *ForceApi) DeleteSObjectByExternalId(externalKey, externalId string, in SObject) (err error) {
-	uri := fmt.Sprintf("%v/%v/%v", forceApi.apiSObjects[in.APIName()].URLs[sObjectKey],
+	return forceApi.DeleteSObjectByExternalIdContext(context.Background(), externalKey, externalId, in)
+}
+
+// DeleteSObjectByExternalIdContext is like DeleteSObjectByExternalId but carries ctx to the underlying http request.
+func (forceApi *ForceApi) DeleteSObjectByExternalIdContext(ctx context.Context, externalKey, externalId string, in SObject) (err error) {
+	uri := fmt.Sprintf("%v/%v/%v", forceApi.sObjectUrl(in.APIName(), sObjectKey),
 		externalKey, externalId)
 
-	err = forceApi.Delete(uri, nil)
+	err = forceApi.DeleteContext(ctx, uri, nil)
 
 	return
 }
@@ -0,0 +1,561 @@
+package force
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opendoor-labs/go-force/forcejson"
+)
+
+const (
+	cometdUri = "/cometd/%v"
+
+	bayeuxVersion      = "1.0"
+	longPolling        = "long-polling"
+	metaHandshake      = "/meta/handshake"
+	metaConnect        = "/meta/connect"
+	metaSubscribe      = "/meta/subscribe"
+	metaDisconnect     = "/meta/disconnect"
+	unknownClientError = "403::Unknown client"
+
+	reconnectHandshake = "handshake"
+	reconnectNone      = "none"
+
+	// ReplayNew subscribes to the events published after subscribing.
+	ReplayNew int64 = -1
+	// ReplayAll subscribes to every event still retained by force.com,
+	// followed by the new ones.
+	ReplayAll int64 = -2
+
+	// streamingMaxBackoff caps the wait between attempts to reconnect after
+	// network errors or failed connects.
+	streamingMaxBackoff = 30 * time.Second
+)
+
+// StreamingSubscription is a Streaming API channel to subscribe to, such as
+// "/topic/AccountUpdates" for a PushTopic or "/event/Order_Placed__e" for a
+// platform event. ReplayId is ReplayNew, ReplayAll, or the replay id of the
+// last event processed, to receive the ones published after it.
+type StreamingSubscription struct {
+	Channel  string
+	ReplayId int64
+}
+
+// StreamingMessage is an event received from a Streaming API channel.
+type StreamingMessage struct {
+	Channel  string
+	ReplayId int64
+	// CreatedDate is when the event was published.
+	CreatedDate string
+	// EventType is "created", "updated", "deleted" or "undeleted" for
+	// PushTopic events.
+	EventType string
+	// Schema is the id of the schema of platform and change events.
+	Schema string
+	// EventUuid identifies platform events, and matches the one returned
+	// by PublishEvent for events published immediately.
+	EventUuid string
+	// Data is the raw data of the event.
+	Data json.RawMessage
+}
+
+type streamingEvent struct {
+	ReplayId    int64  `json:"replayId"`
+	CreatedDate string `json:"createdDate"`
+	Type        string `json:"type"`
+	EventUuid   string `json:"EventUuid"`
+}
+
+type streamingData struct {
+	Event   streamingEvent  `json:"event"`
+	Schema  string          `json:"schema"`
+	SObject json.RawMessage `json:"sobject"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Decode unmarshals the record of a PushTopic event, or the payload of a
+// platform, change or generic event, into out with the same rules as the
+// rest of the api, so the SObject structs used with InsertSObject and
+// friends can be used here too.
+func (m *StreamingMessage) Decode(out interface{}) error {
+	data := &streamingData{}
+	if err := json.Unmarshal(m.Data, data); err != nil {
+		return fmt.Errorf("Unable to unmarshal streaming message: %v", err)
+	}
+
+	raw := data.SObject
+	if len(raw) == 0 {
+		raw = data.Payload
+	}
+	if len(raw) == 0 {
+		return fmt.Errorf("Streaming message on %v has no record or payload", m.Channel)
+	}
+
+	if err := forcejson.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("Unable to unmarshal streaming message: %v", err)
+	}
+
+	return nil
+}
+
+type bayeuxAdvice struct {
+	Reconnect string `json:"reconnect,omitempty"`
+	Interval  int64  `json:"interval,omitempty"`
+	Timeout   int64  `json:"timeout,omitempty"`
+}
+
+type bayeuxMessage struct {
+	Channel                  string                 `json:"channel"`
+	Id                       string                 `json:"id,omitempty"`
+	ClientId                 string                 `json:"clientId,omitempty"`
+	Version                  string                 `json:"version,omitempty"`
+	MinimumVersion           string                 `json:"minimumVersion,omitempty"`
+	SupportedConnectionTypes []string               `json:"supportedConnectionTypes,omitempty"`
+	ConnectionType           string                 `json:"connectionType,omitempty"`
+	Subscription             string                 `json:"subscription,omitempty"`
+	Successful               bool                   `json:"successful,omitempty"`
+	Error                    string                 `json:"error,omitempty"`
+	Advice                   *bayeuxAdvice          `json:"advice,omitempty"`
+	Ext                      map[string]interface{} `json:"ext,omitempty"`
+	Data                     json.RawMessage        `json:"data,omitempty"`
+}
+
+// bayeuxError is a failed reply to a Bayeux meta message.
+type bayeuxError struct {
+	channel string
+	message string
+	advice  *bayeuxAdvice
+}
+
+func (e *bayeuxError) Error() string {
+	return fmt.Sprintf("Streaming %v failed: %v", e.channel, e.message)
+}
+
+// errSessionRenewed is returned for requests rejected because the access
+// token expired, once a new one was obtained. force.com has then forgotten
+// about the client, which has to handshake again.
+var errSessionRenewed = errors.New("Streaming session expired: access token renewed")
+
+// needsHandshake reports whether the client has to handshake again before
+// going on, because force.com forgot about it.
+func (e *bayeuxError) needsHandshake() bool {
+	return strings.HasPrefix(e.message, unknownClientError) || (e.advice != nil && e.advice.Reconnect == reconnectHandshake)
+}
+
+// streamingDecodeError is returned for events that can't be decoded. It stops
+// the client, which would only receive the event again after reconnecting.
+type streamingDecodeError struct {
+	err error
+}
+
+func (e *streamingDecodeError) Error() string {
+	return fmt.Sprintf("Unable to unmarshal streaming message: %v", e.err)
+}
+
+// StreamingClient receives events from Streaming API channels over CometD
+// long polling, using the instance url and access token of a ForceApi.
+// Build one with NewStreamingClient and call Start:
+//
+//	client := forceApi.NewStreamingClient(force.StreamingSubscription{
+//		Channel:  "/topic/AccountUpdates",
+//		ReplayId: force.ReplayNew,
+//	})
+//	messages, err := client.Start(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	for message := range messages {
+//		account := &Account{}
+//		if err := message.Decode(account); err != nil {
+//			return err
+//		}
+//		...
+//	}
+//	return client.Err()
+//
+// The client handshakes again and resubscribes from the last received event
+// whenever force.com forgets about it, when the access token expires, and
+// after network errors. An event that can't be decoded stops the client, as
+// it would be received again after reconnecting. The http client of the ForceApi must not time out
+// connect requests before force.com answers them, which takes up to two
+// minutes when no event is published.
+type StreamingClient struct {
+	forceApi      *ForceApi
+	subscriptions []*StreamingSubscription
+	messages      chan *StreamingMessage
+
+	// clientId and cookies identify the client to force.com. They are only
+	// used by the goroutine running the client once started.
+	clientId string
+	cookies  map[string]string
+	advice   bayeuxAdvice
+
+	mu  sync.Mutex
+	err error
+}
+
+// NewStreamingClient returns a client subscribing to the given channels.
+func (forceApi *ForceApi) NewStreamingClient(subscriptions ...StreamingSubscription) *StreamingClient {
+	client := &StreamingClient{
+		forceApi: forceApi,
+		messages: make(chan *StreamingMessage),
+		cookies:  make(map[string]string),
+	}
+	for i := range subscriptions {
+		subscription := subscriptions[i]
+		client.subscriptions = append(client.subscriptions, &subscription)
+	}
+
+	return client
+}
+
+// Start handshakes and subscribes to the channels, then receives events in
+// the background until ctx is done or the client fails for good. The
+// returned channel delivers the events and is closed when the client stops;
+// Err tells why.
+func (c *StreamingClient) Start(ctx context.Context) (<-chan *StreamingMessage, error) {
+	err := c.handshakeAndSubscribe(ctx)
+	if err == errSessionRenewed {
+		err = c.handshakeAndSubscribe(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	go c.run(ctx)
+
+	return c.messages, nil
+}
+
+// Err returns the error that stopped the client, or nil while it runs or
+// when it stopped because its context was done.
+func (c *StreamingClient) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+// ReplayIds returns the replay id of the last event received on each
+// channel, or the replay id subscribed with when none was received yet.
+func (c *StreamingClient) ReplayIds() map[string]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	replayIds := make(map[string]int64, len(c.subscriptions))
+	for _, subscription := range c.subscriptions {
+		replayIds[subscription.Channel] = subscription.ReplayId
+	}
+
+	return replayIds
+}
+
+func (c *StreamingClient) run(ctx context.Context) {
+	defer close(c.messages)
+
+	err := c.connectLoop(ctx)
+	if ctx.Err() != nil {
+		err = nil
+		// Let force.com drop the client right away instead of waiting for
+		// it to time out.
+		c.disconnect()
+	}
+
+	c.mu.Lock()
+	c.err = err
+	c.mu.Unlock()
+}
+
+func (c *StreamingClient) connectLoop(ctx context.Context) error {
+	failures := 0
+	for {
+		if c.advice.Interval > 0 {
+			if err := sleepContext(ctx, time.Duration(c.advice.Interval)*time.Millisecond); err != nil {
+				return err
+			}
+		}
+
+		err := c.connect(ctx)
+		if err == nil {
+			failures = 0
+			continue
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, ok := err.(*streamingDecodeError); ok {
+			return err
+		}
+
+		if bayeuxErr, ok := err.(*bayeuxError); ok && !bayeuxErr.needsHandshake() {
+			if bayeuxErr.advice != nil && bayeuxErr.advice.Reconnect == reconnectNone {
+				return err
+			}
+			failures++
+			if err := sleepContext(ctx, streamingBackoff(failures)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Network errors, forgotten clients and renewed sessions all call
+		// for a new handshake, after waiting a bit if they keep happening.
+		for {
+			if failures > 0 {
+				if err := sleepContext(ctx, streamingBackoff(failures)); err != nil {
+					return err
+				}
+			}
+			failures++
+
+			err = c.handshakeAndSubscribe(ctx)
+			if err == nil {
+				break
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if _, ok := err.(*bayeuxError); ok {
+				// force.com refused the handshake or a subscription,
+				// which won't get better by retrying.
+				return err
+			}
+			c.forceApi.trace("Streaming reconnect failed:", err, "%v")
+		}
+	}
+}
+
+// streamingBackoff returns how long to wait before the given reconnection
+// attempt, counting from 1.
+func streamingBackoff(failures int) time.Duration {
+	wait := time.Second
+	for i := 1; i < failures && wait < streamingMaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > streamingMaxBackoff {
+		wait = streamingMaxBackoff
+	}
+
+	return wait
+}
+
+func (c *StreamingClient) handshakeAndSubscribe(ctx context.Context) error {
+	if err := c.handshake(ctx); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	subscriptions := make([]StreamingSubscription, len(c.subscriptions))
+	for i, subscription := range c.subscriptions {
+		subscriptions[i] = *subscription
+	}
+	c.mu.Unlock()
+
+	for _, subscription := range subscriptions {
+		if err := c.subscribe(ctx, subscription); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *StreamingClient) handshake(ctx context.Context) error {
+	c.clientId = ""
+	c.cookies = make(map[string]string)
+	c.advice = bayeuxAdvice{}
+
+	replies, err := c.send(ctx, &bayeuxMessage{
+		Channel:                  metaHandshake,
+		Version:                  bayeuxVersion,
+		MinimumVersion:           bayeuxVersion,
+		SupportedConnectionTypes: []string{longPolling},
+		Ext:                      map[string]interface{}{"replay": true},
+	})
+	if err != nil {
+		return err
+	}
+
+	reply, err := metaReply(replies, metaHandshake)
+	if err != nil {
+		return err
+	}
+
+	c.clientId = reply.ClientId
+	if reply.Advice != nil {
+		c.advice = *reply.Advice
+	}
+
+	return nil
+}
+
+func (c *StreamingClient) subscribe(ctx context.Context, subscription StreamingSubscription) error {
+	replies, err := c.send(ctx, &bayeuxMessage{
+		Channel:      metaSubscribe,
+		ClientId:     c.clientId,
+		Subscription: subscription.Channel,
+		Ext: map[string]interface{}{
+			"replay": map[string]int64{subscription.Channel: subscription.ReplayId},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = metaReply(replies, metaSubscribe)
+	return err
+}
+
+// connect makes a single long polling request and delivers the events it
+// returns.
+func (c *StreamingClient) connect(ctx context.Context) error {
+	replies, err := c.send(ctx, &bayeuxMessage{
+		Channel:        metaConnect,
+		ClientId:       c.clientId,
+		ConnectionType: longPolling,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, reply := range replies {
+		if strings.HasPrefix(reply.Channel, "/meta/") {
+			continue
+		}
+
+		if err := c.deliver(ctx, reply); err != nil {
+			return err
+		}
+	}
+
+	reply, err := metaReply(replies, metaConnect)
+	if reply != nil && reply.Advice != nil {
+		c.advice = *reply.Advice
+	}
+
+	return err
+}
+
+func (c *StreamingClient) deliver(ctx context.Context, reply *bayeuxMessage) error {
+	data := &streamingData{}
+	if err := json.Unmarshal(reply.Data, data); err != nil {
+		return &streamingDecodeError{err}
+	}
+
+	message := &StreamingMessage{
+		Channel:     reply.Channel,
+		ReplayId:    data.Event.ReplayId,
+		CreatedDate: data.Event.CreatedDate,
+		EventType:   data.Event.Type,
+		Schema:      data.Schema,
+		EventUuid:   data.Event.EventUuid,
+		Data:        reply.Data,
+	}
+
+	select {
+	case c.messages <- message:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	// Only move past the event once it was handed over, so that it is
+	// received again after reconnecting otherwise.
+	c.mu.Lock()
+	for _, subscription := range c.subscriptions {
+		if subscription.Channel == message.Channel {
+			subscription.ReplayId = message.ReplayId
+		}
+	}
+	c.mu.Unlock()
+
+	return nil
+}
+
+func (c *StreamingClient) disconnect() {
+	if c.clientId == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c.send(ctx, &bayeuxMessage{
+		Channel:  metaDisconnect,
+		ClientId: c.clientId,
+	})
+}
+
+// send posts message to the CometD endpoint and returns the replies. The
+// access token is renewed when it expired.
+func (c *StreamingClient) send(ctx context.Context, message *bayeuxMessage) ([]*bayeuxMessage, error) {
+	body, err := json.Marshal([]*bayeuxMessage{message})
+	if err != nil {
+		return nil, fmt.Errorf("Error marshaling streaming message: %v", err)
+	}
+
+	path := fmt.Sprintf(cometdUri, strings.TrimPrefix(c.forceApi.apiVersion, "v"))
+	if len(c.cookies) != 0 {
+		cookies := make([]string, 0, len(c.cookies))
+		for name, value := range c.cookies {
+			cookies = append(cookies, (&http.Cookie{Name: name, Value: value}).String())
+		}
+		ctx = ContextWithHeader(ctx, "Cookie", strings.Join(cookies, "; "))
+	}
+
+	accessToken := c.forceApi.oauth.token().AccessToken
+	resp, respBytes, err := c.forceApi.send(ctx, "POST", path, nil, contentType, responseType, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cookie := range resp.Cookies() {
+		c.cookies[cookie.Name] = cookie.Value
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		// The access token expired. Renew it, after which force.com has
+		// forgotten about the client.
+		if err := c.forceApi.oauth.reauthenticate(ctx, accessToken); err != nil {
+			return nil, err
+		}
+		return nil, errSessionRenewed
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, &RequestError{
+			StatusCode: resp.StatusCode,
+			Method:     "POST",
+			Path:       path,
+			Body:       respBytes,
+		}
+	}
+
+	var replies []*bayeuxMessage
+	if err := json.Unmarshal(respBytes, &replies); err != nil {
+		return nil, fmt.Errorf("Unable to unmarshal streaming response: %v", err)
+	}
+
+	return replies, nil
+}
+
+// metaReply returns the reply to the meta message sent on channel, or an
+// error if it failed.
+func metaReply(replies []*bayeuxMessage, channel string) (*bayeuxMessage, error) {
+	for _, reply := range replies {
+		if reply.Channel != channel {
+			continue
+		}
+		if !reply.Successful {
+			return reply, &bayeuxError{channel: channel, message: reply.Error, advice: reply.Advice}
+		}
+		return reply, nil
+	}
+
+	return nil, fmt.Errorf("Streaming %v failed: no reply", channel)
+}
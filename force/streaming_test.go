@@ -0,0 +1,355 @@
+package force_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/forcefakes"
+)
+
+// fakeCometd answers CometD requests with the scripted handshake and connect
+// replies, then accepts further handshakes and holds further connect requests
+// until they are cancelled.
+type fakeCometd struct {
+	mu         sync.Mutex
+	requests   []map[string]interface{}
+	cookies    []string
+	tokens     []string
+	handshakes []func() *http.Response
+	connects   []func() *http.Response
+	logins     int
+}
+
+func (f *fakeCometd) Do(req *http.Request) (*http.Response, error) {
+	if strings.HasSuffix(req.URL.Path, "/services/oauth2/token") {
+		f.mu.Lock()
+		f.logins++
+		token := fmt.Sprintf("at%v", f.logins)
+		f.mu.Unlock()
+		return NewFakeResponse(`{"access_token": "`+token+`", "instance_url": "https://example.my.salesforce.com"}`, 200), nil
+	}
+
+	body, _ := ioutil.ReadAll(req.Body)
+	var messages []map[string]interface{}
+	if err := json.Unmarshal(body, &messages); err != nil || len(messages) != 1 {
+		return nil, fmt.Errorf("unexpected request: %s", body)
+	}
+	message := messages[0]
+
+	f.mu.Lock()
+	f.requests = append(f.requests, message)
+	f.cookies = append(f.cookies, req.Header.Get("Cookie"))
+	f.tokens = append(f.tokens, req.Header.Get("Authorization"))
+	var next func() *http.Response
+	if message["channel"] == "/meta/connect" && len(f.connects) != 0 {
+		next, f.connects = f.connects[0], f.connects[1:]
+	}
+	if message["channel"] == "/meta/handshake" && len(f.handshakes) != 0 {
+		next, f.handshakes = f.handshakes[0], f.handshakes[1:]
+	}
+	f.mu.Unlock()
+
+	switch message["channel"] {
+	case "/meta/handshake":
+		if next != nil {
+			return next(), nil
+		}
+		return handshakeReply(), nil
+	case "/meta/subscribe":
+		return NewFakeResponse(`[{"channel": "/meta/subscribe", "subscription": "`+message["subscription"].(string)+`", "successful": true}]`, 200), nil
+	case "/meta/connect":
+		if next != nil {
+			return next(), nil
+		}
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	default:
+		return NewFakeResponse(`[{"channel": "`+message["channel"].(string)+`", "successful": true}]`, 200), nil
+	}
+}
+
+func (f *fakeCometd) channels() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	channels := make([]string, len(f.requests))
+	for i, request := range f.requests {
+		channels[i] = request["channel"].(string)
+	}
+	return channels
+}
+
+func (f *fakeCometd) request(i int) map[string]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.requests[i]
+}
+
+func (f *fakeCometd) cookie(i int) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.cookies[i]
+}
+
+func (f *fakeCometd) token(i int) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.tokens[i]
+}
+
+const accountEvent = `{
+	"channel": "/topic/AccountUpdates",
+	"data": {
+		"event": {"createdDate": "2024-01-01T00:00:00.000Z", "replayId": %v, "type": "updated"},
+		"sobject": {"Id": "001A", "Name": "Acme %v"}
+	}
+}`
+
+func handshakeReply() *http.Response {
+	resp := NewFakeResponse(`[{"channel": "/meta/handshake", "clientId": "client1", "successful": true}]`, 200)
+	resp.Header = http.Header{"Set-Cookie": {"BAYEUX_BROWSER=abc; Path=/"}}
+	return resp
+}
+
+func expiredSession() *http.Response {
+	return NewFakeResponse(`[{"channel": "/meta/handshake", "successful": false, "error": "401::Authentication invalid"}]`, 401)
+}
+
+func connectReply(events ...string) func() *http.Response {
+	return func() *http.Response {
+		replies := append(events, `{"channel": "/meta/connect", "successful": true}`)
+		return NewFakeResponse("["+strings.Join(replies, ",")+"]", 200)
+	}
+}
+
+var _ = Describe("Streaming", func() {
+	var (
+		server   *fakeCometd
+		forceApi *force.ForceApi
+		ctx      context.Context
+		cancel   context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		server = &fakeCometd{}
+		httpClient := &forcefakes.FakeHttpClient{DoStub: server.Do}
+
+		var err error
+		forceApi, err = force.New(
+			force.WithHttpClient(httpClient),
+			force.WithPassword("id", "secret", "user", "pass", "token"),
+			force.WithLazyMetadata(),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx, cancel = context.WithCancel(context.Background())
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	It("should subscribe and deliver events", func() {
+		server.connects = []func() *http.Response{
+			connectReply(fmt.Sprintf(accountEvent, 5, 1), fmt.Sprintf(accountEvent, 6, 2)),
+		}
+
+		client := forceApi.NewStreamingClient(force.StreamingSubscription{Channel: "/topic/AccountUpdates", ReplayId: 4})
+		messages, err := client.Start(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		message := <-messages
+		Expect(message.Channel).To(Equal("/topic/AccountUpdates"))
+		Expect(message.ReplayId).To(Equal(int64(5)))
+		Expect(message.EventType).To(Equal("updated"))
+		Expect(message.CreatedDate).To(Equal("2024-01-01T00:00:00.000Z"))
+
+		account := &CompositeSObject{}
+		Expect(message.Decode(account)).To(Succeed())
+		Expect(account).To(Equal(&CompositeSObject{Id: "001A", Name: "Acme 1"}))
+
+		message = <-messages
+		Expect(message.ReplayId).To(Equal(int64(6)))
+		Eventually(client.ReplayIds).Should(Equal(map[string]int64{"/topic/AccountUpdates": 6}))
+
+		handshake := server.request(0)
+		Expect(handshake["channel"]).To(Equal("/meta/handshake"))
+		Expect(handshake["supportedConnectionTypes"]).To(Equal([]interface{}{"long-polling"}))
+		Expect(handshake["ext"]).To(Equal(map[string]interface{}{"replay": true}))
+
+		subscribe := server.request(1)
+		Expect(subscribe["channel"]).To(Equal("/meta/subscribe"))
+		Expect(subscribe["clientId"]).To(Equal("client1"))
+		Expect(subscribe["subscription"]).To(Equal("/topic/AccountUpdates"))
+		Expect(subscribe["ext"]).To(Equal(map[string]interface{}{
+			"replay": map[string]interface{}{"/topic/AccountUpdates": float64(4)},
+		}))
+
+		connect := server.request(2)
+		Expect(connect["channel"]).To(Equal("/meta/connect"))
+		Expect(connect["connectionType"]).To(Equal("long-polling"))
+		Expect(server.cookie(1)).To(Equal("BAYEUX_BROWSER=abc"))
+
+		cancel()
+		Eventually(messages).Should(BeClosed())
+		Expect(client.Err()).NotTo(HaveOccurred())
+		Eventually(server.channels).Should(ContainElement("/meta/disconnect"))
+	})
+
+	It("should handshake again and resume when the client is unknown", func() {
+		server.connects = []func() *http.Response{
+			connectReply(fmt.Sprintf(accountEvent, 5, 1)),
+			func() *http.Response {
+				return NewFakeResponse(`[{"channel": "/meta/connect", "successful": false, "error": "403::Unknown client", "advice": {"reconnect": "handshake"}}]`, 200)
+			},
+			connectReply(fmt.Sprintf(accountEvent, 6, 2)),
+		}
+
+		client := forceApi.NewStreamingClient(force.StreamingSubscription{Channel: "/topic/AccountUpdates", ReplayId: force.ReplayNew})
+		messages, err := client.Start(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect((<-messages).ReplayId).To(Equal(int64(5)))
+		Expect((<-messages).ReplayId).To(Equal(int64(6)))
+
+		Expect(server.channels()[:7]).To(Equal([]string{
+			"/meta/handshake", "/meta/subscribe", "/meta/connect", "/meta/connect",
+			"/meta/handshake", "/meta/subscribe", "/meta/connect",
+		}))
+		Expect(server.request(5)["ext"]).To(Equal(map[string]interface{}{
+			"replay": map[string]interface{}{"/topic/AccountUpdates": float64(5)},
+		}))
+	})
+
+	It("should renew the access token when it expires", func() {
+		server.connects = []func() *http.Response{
+			func() *http.Response {
+				return NewFakeResponse(`[{"channel": "/meta/connect", "successful": false, "error": "401::Authentication invalid"}]`, 401)
+			},
+			connectReply(fmt.Sprintf(accountEvent, 7, 1)),
+		}
+
+		client := forceApi.NewStreamingClient(force.StreamingSubscription{Channel: "/event/Order_Placed__e", ReplayId: force.ReplayAll})
+		messages, err := client.Start(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect((<-messages).ReplayId).To(Equal(int64(7)))
+		Expect(forceApi.GetAccessToken()).To(Equal("at2"))
+		Expect(server.channels()[:6]).To(Equal([]string{
+			"/meta/handshake", "/meta/subscribe", "/meta/connect",
+			"/meta/handshake", "/meta/subscribe", "/meta/connect",
+		}))
+		Expect(server.token(3)).To(Equal("Bearer at2"))
+	})
+
+	It("should renew the access token when the handshake is rejected", func() {
+		server.handshakes = []func() *http.Response{expiredSession}
+		server.connects = []func() *http.Response{
+			connectReply(fmt.Sprintf(accountEvent, 7, 1)),
+		}
+
+		client := forceApi.NewStreamingClient(force.StreamingSubscription{Channel: "/event/Order_Placed__e", ReplayId: force.ReplayAll})
+		messages, err := client.Start(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect((<-messages).ReplayId).To(Equal(int64(7)))
+		Expect(forceApi.GetAccessToken()).To(Equal("at2"))
+		Expect(server.channels()[:4]).To(Equal([]string{
+			"/meta/handshake", "/meta/handshake", "/meta/subscribe", "/meta/connect",
+		}))
+		Expect(server.token(1)).To(Equal("Bearer at2"))
+	})
+
+	It("should keep reconnecting when the handshake is rejected while reconnecting", func() {
+		server.handshakes = []func() *http.Response{handshakeReply, expiredSession}
+		server.connects = []func() *http.Response{
+			func() *http.Response {
+				return NewFakeResponse(`[{"channel": "/meta/connect", "successful": false, "error": "403::Unknown client", "advice": {"reconnect": "handshake"}}]`, 200)
+			},
+			connectReply(fmt.Sprintf(accountEvent, 8, 1)),
+		}
+
+		client := forceApi.NewStreamingClient(force.StreamingSubscription{Channel: "/event/Order_Placed__e", ReplayId: force.ReplayAll})
+		messages, err := client.Start(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		var message *force.StreamingMessage
+		Eventually(messages, 5*time.Second).Should(Receive(&message))
+		Expect(message.ReplayId).To(Equal(int64(8)))
+		Expect(client.Err()).NotTo(HaveOccurred())
+		Expect(forceApi.GetAccessToken()).To(Equal("at2"))
+		Expect(server.channels()[:7]).To(Equal([]string{
+			"/meta/handshake", "/meta/subscribe", "/meta/connect",
+			"/meta/handshake", "/meta/handshake", "/meta/subscribe", "/meta/connect",
+		}))
+	})
+
+	It("should back off before retrying a failed connect", func() {
+		server.connects = []func() *http.Response{
+			func() *http.Response {
+				return NewFakeResponse(`[{"channel": "/meta/connect", "successful": false, "error": "500::Server error", "advice": {"reconnect": "retry"}}]`, 200)
+			},
+			connectReply(fmt.Sprintf(accountEvent, 9, 1)),
+		}
+
+		client := forceApi.NewStreamingClient(force.StreamingSubscription{Channel: "/event/Order_Placed__e", ReplayId: force.ReplayAll})
+		start := time.Now()
+		messages, err := client.Start(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		var message *force.StreamingMessage
+		Eventually(messages, 5*time.Second).Should(Receive(&message))
+		Expect(message.ReplayId).To(Equal(int64(9)))
+		Expect(time.Since(start)).To(BeNumerically(">=", time.Second))
+		Expect(server.channels()[:4]).To(Equal([]string{
+			"/meta/handshake", "/meta/subscribe", "/meta/connect", "/meta/connect",
+		}))
+	})
+
+	It("should stop at an event that can't be decoded", func() {
+		server.connects = []func() *http.Response{
+			connectReply(fmt.Sprintf(accountEvent, 5, 1), `{"channel": "/topic/AccountUpdates", "data": "oops"}`),
+		}
+
+		client := forceApi.NewStreamingClient(force.StreamingSubscription{Channel: "/topic/AccountUpdates", ReplayId: force.ReplayNew})
+		messages, err := client.Start(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect((<-messages).ReplayId).To(Equal(int64(5)))
+		Eventually(messages).Should(BeClosed())
+		Expect(client.Err()).To(MatchError(ContainSubstring("Unable to unmarshal streaming message")))
+		Expect(client.ReplayIds()).To(Equal(map[string]int64{"/topic/AccountUpdates": 5}))
+		Expect(server.channels()).To(Equal([]string{"/meta/handshake", "/meta/subscribe", "/meta/connect"}))
+	})
+
+	It("should stop when a subscription is refused", func() {
+		httpClient := &forcefakes.FakeHttpClient{}
+		httpClient.DoReturnsOnCall(0, NewFakeResponse(`{"access_token": "at", "instance_url": "https://example.my.salesforce.com"}`, 200), nil)
+		httpClient.DoReturnsOnCall(1, NewFakeResponse(`[{"channel": "/meta/handshake", "clientId": "client1", "successful": true}]`, 200), nil)
+		httpClient.DoReturnsOnCall(2, NewFakeResponse(`[{"channel": "/meta/subscribe", "successful": false, "error": "400::The channel you requested to subscribe to does not exist {/topic/Unknown}"}]`, 200), nil)
+
+		forceApi, err := force.New(
+			force.WithHttpClient(httpClient),
+			force.WithPassword("id", "secret", "user", "pass", "token"),
+			force.WithLazyMetadata(),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = forceApi.NewStreamingClient(force.StreamingSubscription{Channel: "/topic/Unknown", ReplayId: force.ReplayNew}).Start(ctx)
+		Expect(err).To(MatchError(ContainSubstring("does not exist")))
+		Expect(httpClient.DoArgsForCall(1).URL.Path).To(Equal("/cometd/" + strings.TrimPrefix(force.DefaultApiVersion, "v")))
+	})
+})
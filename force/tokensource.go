@@ -0,0 +1,225 @@
+package force
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"net/http"
+)
+
+// Token is an OAuth access token along with the instance it is valid for.
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	InstanceUrl  string `json:"instance_url"`
+	Id           string `json:"id,omitempty"`
+	IssuedAt     string `json:"issued_at,omitempty"`
+	Signature    string `json:"signature,omitempty"`
+}
+
+// TokenSource provides the access tokens ForceApi authenticates its requests
+// with. ForceApi asks for a token once and keeps using it until force.com
+// rejects it as expired, at which point it asks for a new one with Refresh.
+// Use WithTokenSource to build a ForceApi from a custom TokenSource.
+//
+// A ForceApi never calls its TokenSource from several goroutines at once, and
+// asks for a single new token when concurrent requests see the same one
+// expire.
+type TokenSource interface {
+	// Token returns a token to authenticate requests with.
+	Token(ctx context.Context) (*Token, error)
+
+	// Refresh returns a new token after the previous one expired.
+	Refresh(ctx context.Context) (*Token, error)
+}
+
+// PasswordTokenSource obtains tokens with the OAuth username-password flow.
+type PasswordTokenSource struct {
+	ClientId      string
+	ClientSecret  string
+	UserName      string
+	Password      string
+	SecurityToken string
+
+	// LoginUrl defaults to https://login.salesforce.com.
+	LoginUrl string
+	// HttpClient defaults to http.DefaultClient.
+	HttpClient HttpClient
+}
+
+// Token requests a new token.
+func (s *PasswordTokenSource) Token(ctx context.Context) (*Token, error) {
+	return s.Refresh(ctx)
+}
+
+// Refresh requests a new token.
+func (s *PasswordTokenSource) Refresh(ctx context.Context) (*Token, error) {
+	oauth := &forceOauth{
+		clientId:      s.ClientId,
+		clientSecret:  s.ClientSecret,
+		userName:      s.UserName,
+		password:      s.Password,
+		securityToken: s.SecurityToken,
+		loginUrl:      s.LoginUrl,
+		httpClient:    s.HttpClient,
+	}
+
+	return oauth.grantToken(ctx)
+}
+
+// RefreshTokenSource obtains tokens with the OAuth refresh token flow. When
+// force.com rotates the refresh token, RefreshToken is updated.
+type RefreshTokenSource struct {
+	ClientId string
+	// ClientSecret may be empty for connected apps that don't require it.
+	ClientSecret string
+	RefreshToken string
+
+	// LoginUrl defaults to https://login.salesforce.com.
+	LoginUrl string
+	// HttpClient defaults to http.DefaultClient.
+	HttpClient HttpClient
+}
+
+// Token requests a new token.
+func (s *RefreshTokenSource) Token(ctx context.Context) (*Token, error) {
+	return s.Refresh(ctx)
+}
+
+// Refresh requests a new token.
+func (s *RefreshTokenSource) Refresh(ctx context.Context) (*Token, error) {
+	oauth := &forceOauth{
+		clientId:     s.ClientId,
+		clientSecret: s.ClientSecret,
+		refreshToken: s.RefreshToken,
+		loginUrl:     s.LoginUrl,
+		httpClient:   s.HttpClient,
+	}
+
+	token, err := oauth.grantToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.RefreshToken != "" {
+		s.RefreshToken = token.RefreshToken
+	}
+
+	return token, nil
+}
+
+func (s *RefreshTokenSource) refreshToken() string {
+	return s.RefreshToken
+}
+
+func (s *RefreshTokenSource) setRefreshToken(refreshToken string) {
+	s.RefreshToken = refreshToken
+}
+
+// refreshTokenHolder is implemented by the token sources using the refresh
+// token flow, so that a refresh token can be persisted along with the access
+// token and restored from storage.
+type refreshTokenHolder interface {
+	refreshToken() string
+	setRefreshToken(refreshToken string)
+}
+
+// JwtTokenSource obtains tokens with the OAuth JWT bearer flow, minting a
+// new assertion signed with Key for every token.
+type JwtTokenSource struct {
+	ClientId string
+	// Subject is the username of the user to obtain tokens for.
+	Subject string
+	// Audience defaults to https://login.salesforce.com, or
+	// https://test.salesforce.com when LoginUrl is the sandbox login url.
+	Audience string
+	Key      crypto.Signer
+
+	// LoginUrl defaults to https://login.salesforce.com.
+	LoginUrl string
+	// HttpClient defaults to http.DefaultClient.
+	HttpClient HttpClient
+}
+
+// Token requests a new token.
+func (s *JwtTokenSource) Token(ctx context.Context) (*Token, error) {
+	return s.Refresh(ctx)
+}
+
+// Refresh requests a new token.
+func (s *JwtTokenSource) Refresh(ctx context.Context) (*Token, error) {
+	oauth := &forceOauth{
+		clientId:    s.ClientId,
+		jwtSubject:  s.Subject,
+		jwtAudience: s.Audience,
+		jwtSigner:   s.Key,
+		loginUrl:    s.LoginUrl,
+		httpClient:  s.HttpClient,
+	}
+
+	return oauth.grantToken(ctx)
+}
+
+// ClientCredentialsTokenSource obtains tokens for the integration user of a
+// connected app with the OAuth client credentials flow.
+type ClientCredentialsTokenSource struct {
+	ClientId     string
+	ClientSecret string
+
+	// LoginUrl is required and must be a My Domain url, such as
+	// "https://example.my.salesforce.com".
+	LoginUrl string
+	// HttpClient defaults to http.DefaultClient.
+	HttpClient HttpClient
+}
+
+// Token requests a new token.
+func (s *ClientCredentialsTokenSource) Token(ctx context.Context) (*Token, error) {
+	return s.Refresh(ctx)
+}
+
+// Refresh requests a new token.
+func (s *ClientCredentialsTokenSource) Refresh(ctx context.Context) (*Token, error) {
+	oauth := &forceOauth{
+		clientId:          s.ClientId,
+		clientSecret:      s.ClientSecret,
+		clientCredentials: true,
+		loginUrl:          s.LoginUrl,
+		httpClient:        s.HttpClient,
+	}
+
+	return oauth.grantToken(ctx)
+}
+
+// StaticTokenSource returns a TokenSource that always returns token, such as
+// an access token obtained elsewhere. It can't be refreshed, so requests
+// fail once the token expires.
+func StaticTokenSource(token *Token) TokenSource {
+	return &staticTokenSource{token: token}
+}
+
+type staticTokenSource struct {
+	token *Token
+}
+
+func (s *staticTokenSource) Token(ctx context.Context) (*Token, error) {
+	return s.token, nil
+}
+
+func (s *staticTokenSource) Refresh(ctx context.Context) (*Token, error) {
+	return nil, fmt.Errorf("Unable to refresh a static access token")
+}
+
+// grantToken obtains a new token with the flow the credentials of oauth call
+// for and returns it.
+func (oauth *forceOauth) grantToken(ctx context.Context) (*Token, error) {
+	if oauth.httpClient == nil {
+		oauth.httpClient = http.DefaultClient
+	}
+
+	if err := oauth.grant(ctx); err != nil {
+		return nil, err
+	}
+
+	return oauth.token(), nil
+}
@@ -0,0 +1,180 @@
+package force_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/forcefakes"
+)
+
+type countingTokenSource struct {
+	tokens    int
+	refreshes int
+}
+
+func (s *countingTokenSource) Token(ctx context.Context) (*force.Token, error) {
+	s.tokens++
+	return &force.Token{AccessToken: "first", InstanceUrl: "https://example.my.salesforce.com"}, nil
+}
+
+func (s *countingTokenSource) Refresh(ctx context.Context) (*force.Token, error) {
+	s.refreshes++
+	return &force.Token{AccessToken: fmt.Sprintf("refreshed%d", s.refreshes), InstanceUrl: "https://example.my.salesforce.com"}, nil
+}
+
+// staleTokenSource hands back the same token on every refresh, like an
+// oauth2.ReuseTokenSource whose token force.com rejected before it expired.
+type staleTokenSource struct {
+	refreshes int
+}
+
+func (s *staleTokenSource) Token(ctx context.Context) (*force.Token, error) {
+	return &force.Token{AccessToken: "stale", InstanceUrl: "https://example.my.salesforce.com"}, nil
+}
+
+func (s *staleTokenSource) Refresh(ctx context.Context) (*force.Token, error) {
+	s.refreshes++
+	return s.Token(ctx)
+}
+
+var _ = Describe("TokenSource", func() {
+	var httpClient forcefakes.FakeHttpClient
+
+	BeforeEach(func() {
+		httpClient = forcefakes.FakeHttpClient{}
+	})
+
+	It("should consult a custom token source", func() {
+		httpClient.DoReturnsOnCall(0, NewFakeResponse(`{"records": []}`, 200), nil)
+		httpClient.DoReturnsOnCall(1, NewFakeResponse(`[{"errorCode": "INVALID_SESSION_ID", "message": "Session expired or invalid"}]`, 401), nil)
+		httpClient.DoReturnsOnCall(2, NewFakeResponse(`{"records": []}`, 200), nil)
+
+		ts := &countingTokenSource{}
+		forceApi, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithTokenSource(ts),
+			force.WithLazyMetadata(),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ts.tokens).To(Equal(1))
+		Expect(forceApi.GetInstanceURL()).To(Equal("https://example.my.salesforce.com"))
+
+		Expect(forceApi.Query("SELECT Id FROM Account", &map[string]interface{}{})).To(Succeed())
+		Expect(httpClient.DoArgsForCall(0).Header.Get("Authorization")).To(Equal("Bearer first"))
+		Expect(ts.refreshes).To(Equal(0))
+
+		Expect(forceApi.Query("SELECT Id FROM Account", &map[string]interface{}{})).To(Succeed())
+		Expect(ts.refreshes).To(Equal(1))
+		Expect(httpClient.DoArgsForCall(2).Header.Get("Authorization")).To(Equal("Bearer refreshed1"))
+
+		Expect(forceApi.RefreshToken()).To(Succeed())
+		Expect(forceApi.GetAccessToken()).To(Equal("refreshed2"))
+	})
+
+	It("should give up when a refreshed token is rejected too", func() {
+		httpClient.DoStub = func(req *http.Request) (*http.Response, error) {
+			return NewFakeResponse(`[{"errorCode": "INVALID_SESSION_ID", "message": "Session expired or invalid"}]`, 401), nil
+		}
+
+		ts := &staleTokenSource{}
+		forceApi, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithTokenSource(ts),
+			force.WithLazyMetadata(),
+			force.WithRequestErrors(),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = forceApi.Query("SELECT Id FROM Account", &map[string]interface{}{})
+		var requestErr *force.RequestError
+		Expect(errors.As(err, &requestErr)).To(BeTrue())
+		Expect(requestErr.StatusCode).To(Equal(401))
+		Expect(ts.refreshes).To(Equal(1))
+		Expect(httpClient.DoCallCount()).To(Equal(2))
+
+		err = forceApi.Search("FIND {Acme}", &map[string][]interface{}{})
+		Expect(errors.As(err, &requestErr)).To(BeTrue())
+		Expect(ts.refreshes).To(Equal(2))
+		Expect(httpClient.DoCallCount()).To(Equal(4))
+	})
+
+	It("should reject a token without an instance url", func() {
+		_, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithTokenSource(force.StaticTokenSource(&force.Token{AccessToken: "token"})),
+		)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject an insecure instance url from a token source", func() {
+		_, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithTokenSource(force.StaticTokenSource(&force.Token{AccessToken: "token", InstanceUrl: "http://attacker.example.com"})),
+		)
+		Expect(err).To(MatchError(ContainSubstring("Invalid instance url")))
+		Expect(httpClient.DoCallCount()).To(Equal(0))
+	})
+
+	It("should reject an insecure instance url on refresh", func() {
+		httpClient.DoReturnsOnCall(0, NewFakeResponse(`[{"errorCode": "INVALID_SESSION_ID", "message": "Session expired or invalid"}]`, 401), nil)
+
+		store := force.NewMemoryTokenStore()
+		store.Save(context.Background(), &force.Token{AccessToken: "stored", InstanceUrl: "https://example.my.salesforce.com"})
+
+		forceApi, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithTokenSource(force.StaticTokenSource(&force.Token{AccessToken: "token", InstanceUrl: "https://example.my.salesforce.com"})),
+			force.WithTokenStore(store),
+			force.WithLazyMetadata(),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Another process sharing the store saved a token for a plain http host.
+		store.Save(context.Background(), &force.Token{AccessToken: "shared", InstanceUrl: "http://attacker.example.com"})
+
+		err = forceApi.Query("SELECT Id FROM Account", &map[string]interface{}{})
+		Expect(err).To(MatchError(ContainSubstring("Invalid instance url")))
+		Expect(forceApi.GetInstanceURL()).To(Equal("https://example.my.salesforce.com"))
+		Expect(httpClient.DoCallCount()).To(Equal(1))
+	})
+
+	It("should not refresh a static token", func() {
+		ts := force.StaticTokenSource(&force.Token{AccessToken: "token", InstanceUrl: "iu"})
+
+		token, err := ts.Token(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.AccessToken).To(Equal("token"))
+
+		_, err = ts.Refresh(context.Background())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should rotate the refresh token", func() {
+		httpClient.DoReturnsOnCall(0, NewFakeResponse(`{"access_token": "at", "instance_url": "https://iu.my.salesforce.com", "refresh_token": "rotated"}`, 200), nil)
+
+		ts := &force.RefreshTokenSource{
+			ClientId:     "id",
+			RefreshToken: "refresh",
+			LoginUrl:     "https://example.my.salesforce.com",
+			HttpClient:   &httpClient,
+		}
+		token, err := ts.Token(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.AccessToken).To(Equal("at"))
+		Expect(ts.RefreshToken).To(Equal("rotated"))
+
+		req := httpClient.DoArgsForCall(0)
+		Expect(req.URL.String()).To(Equal("https://example.my.salesforce.com/services/oauth2/token"))
+		body, _ := ioutil.ReadAll(req.Body)
+		form, _ := url.ParseQuery(string(body))
+		Expect(form.Get("refresh_token")).To(Equal("refresh"))
+	})
+})
@@ -0,0 +1,198 @@
+package force
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// TokenStore persists the access token of a ForceApi, so that it survives
+// restarts and can be shared between processes without each of them logging
+// in. Use WithTokenStore to have a ForceApi load and save its tokens.
+type TokenStore interface {
+	// Load returns the stored token, or nil when none was stored yet.
+	Load(ctx context.Context) (*Token, error)
+
+	// Save stores token, replacing the previous one.
+	Save(ctx context.Context, token *Token) error
+}
+
+// MemoryTokenStore keeps a token in memory. It can be shared by several
+// ForceApi in the same process.
+type MemoryTokenStore struct {
+	mu    sync.Mutex
+	token *Token
+}
+
+// NewMemoryTokenStore returns an empty MemoryTokenStore.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{}
+}
+
+// Load returns the stored token, or nil when none was stored yet.
+func (s *MemoryTokenStore) Load(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == nil {
+		return nil, nil
+	}
+
+	token := *s.token
+	return &token, nil
+}
+
+// Save stores token, replacing the previous one.
+func (s *MemoryTokenStore) Save(ctx context.Context, token *Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *token
+	s.token = &stored
+	return nil
+}
+
+// FileTokenStore keeps a token in a json file readable only by its owner.
+// The file is replaced atomically, so processes sharing it never read a
+// partially written token.
+type FileTokenStore struct {
+	path string
+}
+
+// NewFileTokenStore returns a FileTokenStore keeping its token at path.
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path: path}
+}
+
+// Load returns the stored token, or nil when the file doesn't exist yet.
+func (s *FileTokenStore) Load(ctx context.Context) (*Token, error) {
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read token file: %v", err)
+	}
+
+	token := &Token{}
+	if err := json.Unmarshal(data, token); err != nil {
+		return nil, fmt.Errorf("Unable to unmarshal token file %v: %v", s.path, err)
+	}
+
+	return token, nil
+}
+
+// Save stores token, replacing the previous one.
+func (s *FileTokenStore) Save(ctx context.Context, token *Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("Unable to marshal token: %v", err)
+	}
+
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("Unable to write token file: %v", err)
+	}
+
+	return nil
+}
+
+// writeFileAtomic replaces the file at path with data, readable only by its
+// owner, so that readers never see it partially written.
+func writeFileAtomic(path string, data []byte) error {
+	// ioutil.TempFile creates the file with 0600 permissions.
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// storedTokenSource loads tokens from store before asking source for new
+// ones, and saves the new ones.
+type storedTokenSource struct {
+	source TokenSource
+	store  TokenStore
+
+	// current is the token last handed out.
+	current *Token
+}
+
+func (s *storedTokenSource) Token(ctx context.Context) (*Token, error) {
+	stored, err := s.store.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil && stored.AccessToken != "" {
+		s.use(stored)
+		return stored, nil
+	}
+
+	token, err := s.source.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, s.save(ctx, token)
+}
+
+// Refresh reuses the stored token when another process already replaced the
+// expired one, and asks source for a new one otherwise.
+func (s *storedTokenSource) Refresh(ctx context.Context) (*Token, error) {
+	stored, err := s.store.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil && stored.AccessToken != "" && (s.current == nil || stored.AccessToken != s.current.AccessToken) {
+		s.use(stored)
+		return stored, nil
+	}
+
+	token, err := s.source.Refresh(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, s.save(ctx, token)
+}
+
+// use hands out a stored token. A refresh token rotated by another process
+// is passed on to the refresh token flow.
+func (s *storedTokenSource) use(token *Token) {
+	s.current = token
+	if source, ok := s.source.(refreshTokenHolder); ok && token.RefreshToken != "" {
+		source.setRefreshToken(token.RefreshToken)
+	}
+}
+
+func (s *storedTokenSource) save(ctx context.Context, token *Token) error {
+	s.current = token
+
+	// Keep the refresh token around when the new token doesn't carry one,
+	// as with the refresh token flow.
+	stored := *token
+	if stored.RefreshToken == "" {
+		if source, ok := s.source.(refreshTokenHolder); ok {
+			stored.RefreshToken = source.refreshToken()
+		}
+	}
+
+	if err := s.store.Save(ctx, &stored); err != nil {
+		return fmt.Errorf("Unable to save token: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package force_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/forcefakes"
+)
+
+var _ = Describe("TokenStore", func() {
+	var httpClient forcefakes.FakeHttpClient
+
+	BeforeEach(func() {
+		httpClient = forcefakes.FakeHttpClient{}
+	})
+
+	It("should save tokens to a file", func() {
+		dir, err := ioutil.TempDir("", "go-force")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		store := force.NewFileTokenStore(filepath.Join(dir, "token.json"))
+		token, err := store.Load(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token).To(BeNil())
+
+		Expect(store.Save(context.Background(), &force.Token{AccessToken: "at", InstanceUrl: "iu"})).To(Succeed())
+		token, err = store.Load(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token).To(Equal(&force.Token{AccessToken: "at", InstanceUrl: "iu"}))
+
+		info, err := os.Stat(filepath.Join(dir, "token.json"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+	})
+
+	It("should save obtained tokens and notify", func() {
+		httpClient.DoReturnsOnCall(0, NewFakeResponse(`{"access_token": "at", "instance_url": "https://iu.my.salesforce.com"}`, 200), nil)
+		httpClient.DoReturnsOnCall(1, NewFakeResponse(`[{"errorCode": "INVALID_SESSION_ID", "message": "Session expired or invalid"}]`, 401), nil)
+		httpClient.DoReturnsOnCall(2, NewFakeResponse(`{"access_token": "renewed", "instance_url": "https://iu.my.salesforce.com"}`, 200), nil)
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`{"records": []}`, 200), nil)
+
+		store := force.NewMemoryTokenStore()
+		var refreshed []string
+		forceApi, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithRefreshToken("id", "", "rt"),
+			force.WithTokenStore(store),
+			force.WithOnTokenRefreshed(func(token *force.Token) {
+				refreshed = append(refreshed, token.AccessToken)
+			}),
+			force.WithLazyMetadata(),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(refreshed).To(Equal([]string{"at"}))
+
+		token, _ := store.Load(context.Background())
+		Expect(token.AccessToken).To(Equal("at"))
+		Expect(token.RefreshToken).To(Equal("rt"))
+
+		Expect(forceApi.Query("SELECT Id FROM Account", &map[string]interface{}{})).To(Succeed())
+		Expect(refreshed).To(Equal([]string{"at", "renewed"}))
+
+		token, _ = store.Load(context.Background())
+		Expect(token.AccessToken).To(Equal("renewed"))
+		Expect(token.RefreshToken).To(Equal("rt"))
+	})
+
+	It("should reuse stored tokens", func() {
+		httpClient.DoReturnsOnCall(0, NewFakeResponse(`[{"errorCode": "INVALID_SESSION_ID", "message": "Session expired or invalid"}]`, 401), nil)
+		httpClient.DoReturnsOnCall(1, NewFakeResponse(`{"records": []}`, 200), nil)
+		httpClient.DoReturnsOnCall(2, NewFakeResponse(`[{"errorCode": "INVALID_SESSION_ID", "message": "Session expired or invalid"}]`, 401), nil)
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`{"access_token": "renewed", "instance_url": "https://iu.my.salesforce.com"}`, 200), nil)
+		httpClient.DoReturnsOnCall(4, NewFakeResponse(`{"records": []}`, 200), nil)
+
+		store := force.NewMemoryTokenStore()
+		store.Save(context.Background(), &force.Token{AccessToken: "stored", RefreshToken: "rotated", InstanceUrl: "https://iu.my.salesforce.com"})
+
+		forceApi, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithRefreshToken("id", "", "rt"),
+			force.WithTokenStore(store),
+			force.WithLazyMetadata(),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(httpClient.DoCallCount()).To(Equal(0))
+		Expect(forceApi.GetAccessToken()).To(Equal("stored"))
+
+		// Another process sharing the store already replaced the token.
+		store.Save(context.Background(), &force.Token{AccessToken: "shared", RefreshToken: "rotated", InstanceUrl: "https://iu.my.salesforce.com"})
+
+		Expect(forceApi.Query("SELECT Id FROM Account", &map[string]interface{}{})).To(Succeed())
+		Expect(httpClient.DoCallCount()).To(Equal(2))
+		Expect(httpClient.DoArgsForCall(1).Header.Get("Authorization")).To(Equal("Bearer shared"))
+
+		// Once the shared token expires too, the rotated refresh token is used.
+		Expect(forceApi.Query("SELECT Id FROM Account", &map[string]interface{}{})).To(Succeed())
+		body, _ := ioutil.ReadAll(httpClient.DoArgsForCall(3).Body)
+		form, _ := url.ParseQuery(string(body))
+		Expect(form.Get("refresh_token")).To(Equal("rotated"))
+		Expect(httpClient.DoArgsForCall(4).Header.Get("Authorization")).To(Equal("Bearer renewed"))
+	})
+})
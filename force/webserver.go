@@ -0,0 +1,171 @@
+package force
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	authorizationCodeGrantType = "authorization_code"
+	authorizePath              = "/services/oauth2/authorize"
+	codeChallengeMethod        = "S256"
+)
+
+// WebServerFlow implements the OAuth web server flow, which lets users
+// authorize a connected app with their own force.com identity:
+//
+//	flow := &force.WebServerFlow{
+//		ClientId:    clientId,
+//		RedirectUri: "https://tool.example.com/oauth/callback",
+//		Scopes:      []string{"api", "refresh_token"},
+//	}
+//
+//	// Redirect the user to the authorize url, keeping state and verifier
+//	// in their session.
+//	verifier, err := force.NewCodeVerifier()
+//	http.Redirect(w, r, flow.AuthCodeUrl(state, verifier), http.StatusFound)
+//
+//	// In the callback, check state and exchange the code.
+//	token, err := flow.Exchange(ctx, r.FormValue("code"), verifier)
+//	forceApi, err := force.New(force.WithTokenSource(flow.TokenSource(token)))
+//
+// The refresh_token scope is required for the ForceApi to renew its session.
+type WebServerFlow struct {
+	ClientId string
+	// ClientSecret may be empty for connected apps that don't require it,
+	// which should then use PKCE.
+	ClientSecret string
+	RedirectUri  string
+	Scopes       []string
+
+	// LoginUrl defaults to https://login.salesforce.com.
+	LoginUrl string
+	// HttpClient defaults to http.DefaultClient.
+	HttpClient HttpClient
+}
+
+// NewCodeVerifier returns a random PKCE code verifier, to be passed to both
+// AuthCodeUrl and Exchange.
+func NewCodeVerifier() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("Unable to generate code verifier: %v", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallenge returns the S256 PKCE code challenge for verifier.
+func CodeChallenge(verifier string) string {
+	digest := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
+
+// AuthCodeUrl returns the url users are redirected to in order to authorize
+// the connected app. state is handed back to the redirect uri unchanged and
+// should be checked there to prevent cross-site request forgery. The PKCE
+// code challenge is only sent when codeVerifier isn't empty.
+func (f *WebServerFlow) AuthCodeUrl(state, codeVerifier string) string {
+	params := url.Values{
+		"response_type": {"code"},
+		"client_id":     {f.ClientId},
+		"redirect_uri":  {f.RedirectUri},
+	}
+	if len(f.Scopes) > 0 {
+		params.Set("scope", strings.Join(f.Scopes, " "))
+	}
+	if state != "" {
+		params.Set("state", state)
+	}
+	if codeVerifier != "" {
+		params.Set("code_challenge", CodeChallenge(codeVerifier))
+		params.Set("code_challenge_method", codeChallengeMethod)
+	}
+
+	return f.loginUrl() + authorizePath + "?" + params.Encode()
+}
+
+// Exchange trades the authorization code handed to the redirect uri for an
+// access token and, when the refresh_token scope was granted, a refresh
+// token. codeVerifier must be the one given to AuthCodeUrl, if any.
+func (f *WebServerFlow) Exchange(ctx context.Context, code, codeVerifier string) (*Token, error) {
+	payload := url.Values{
+		"grant_type":   {authorizationCodeGrantType},
+		"code":         {code},
+		"client_id":    {f.ClientId},
+		"redirect_uri": {f.RedirectUri},
+	}
+	if f.ClientSecret != "" {
+		payload.Set("client_secret", f.ClientSecret)
+	}
+	if codeVerifier != "" {
+		payload.Set("code_verifier", codeVerifier)
+	}
+
+	oauth := &forceOauth{
+		loginUrl:   f.LoginUrl,
+		httpClient: f.HttpClient,
+	}
+	if oauth.httpClient == nil {
+		oauth.httpClient = http.DefaultClient
+	}
+
+	if err := oauth.requestToken(ctx, payload); err != nil {
+		return nil, err
+	}
+
+	return oauth.token(), nil
+}
+
+// TokenSource returns a TokenSource that starts out with token, as returned
+// by Exchange or loaded from storage, and renews it with its refresh token.
+func (f *WebServerFlow) TokenSource(token *Token) TokenSource {
+	return &webServerTokenSource{
+		token: token,
+		refresh: &RefreshTokenSource{
+			ClientId:     f.ClientId,
+			ClientSecret: f.ClientSecret,
+			RefreshToken: token.RefreshToken,
+			LoginUrl:     f.LoginUrl,
+			HttpClient:   f.HttpClient,
+		},
+	}
+}
+
+func (f *WebServerFlow) loginUrl() string {
+	if f.LoginUrl != "" {
+		return strings.TrimRight(f.LoginUrl, "/")
+	}
+	return loginBaseUri
+}
+
+type webServerTokenSource struct {
+	token   *Token
+	refresh *RefreshTokenSource
+}
+
+func (s *webServerTokenSource) Token(ctx context.Context) (*Token, error) {
+	return s.token, nil
+}
+
+func (s *webServerTokenSource) refreshToken() string {
+	return s.refresh.RefreshToken
+}
+
+func (s *webServerTokenSource) setRefreshToken(refreshToken string) {
+	s.refresh.RefreshToken = refreshToken
+}
+
+func (s *webServerTokenSource) Refresh(ctx context.Context) (*Token, error) {
+	if s.refresh.RefreshToken == "" {
+		return nil, fmt.Errorf("Unable to refresh access token: no refresh token was granted")
+	}
+
+	return s.refresh.Refresh(ctx)
+}
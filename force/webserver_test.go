@@ -0,0 +1,105 @@
+package force_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/force"
+	"github.com/opendoor-labs/go-force/force/forcefakes"
+)
+
+var _ = Describe("WebServerFlow", func() {
+	var (
+		httpClient forcefakes.FakeHttpClient
+		flow       *force.WebServerFlow
+	)
+
+	BeforeEach(func() {
+		httpClient = forcefakes.FakeHttpClient{}
+		flow = &force.WebServerFlow{
+			ClientId:    "id",
+			RedirectUri: "https://tool.example.com/callback",
+			Scopes:      []string{"api", "refresh_token"},
+			LoginUrl:    "https://example.my.salesforce.com/",
+			HttpClient:  &httpClient,
+		}
+	})
+
+	It("should compute the S256 code challenge", func() {
+		// Example from RFC 7636, appendix B.
+		Expect(force.CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")).To(Equal("E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"))
+
+		verifier, err := force.NewCodeVerifier()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verifier).To(HaveLen(43))
+	})
+
+	It("should build the authorize url", func() {
+		u, err := url.Parse(flow.AuthCodeUrl("xyz", "verifier"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(u.Scheme + "://" + u.Host + u.Path).To(Equal("https://example.my.salesforce.com/services/oauth2/authorize"))
+		Expect(u.Query()).To(Equal(url.Values{
+			"response_type":         {"code"},
+			"client_id":             {"id"},
+			"redirect_uri":          {"https://tool.example.com/callback"},
+			"scope":                 {"api refresh_token"},
+			"state":                 {"xyz"},
+			"code_challenge":        {force.CodeChallenge("verifier")},
+			"code_challenge_method": {"S256"},
+		}))
+
+		u, _ = url.Parse((&force.WebServerFlow{ClientId: "id"}).AuthCodeUrl("", ""))
+		Expect(u.Host).To(Equal("login.salesforce.com"))
+		Expect(u.Query()).NotTo(HaveKey("code_challenge"))
+	})
+
+	It("should exchange the code and refresh with the refresh token", func() {
+		httpClient.DoReturnsOnCall(0, NewFakeResponse(`{"access_token": "at", "refresh_token": "rt", "instance_url": "https://example.my.salesforce.com"}`, 200), nil)
+		httpClient.DoReturnsOnCall(1, NewFakeResponse(`[{"errorCode": "INVALID_SESSION_ID", "message": "Session expired or invalid"}]`, 401), nil)
+		httpClient.DoReturnsOnCall(2, NewFakeResponse(`{"access_token": "renewed", "instance_url": "https://example.my.salesforce.com"}`, 200), nil)
+		httpClient.DoReturnsOnCall(3, NewFakeResponse(`{"records": []}`, 200), nil)
+
+		token, err := flow.Exchange(context.Background(), "code", "verifier")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.AccessToken).To(Equal("at"))
+		Expect(token.RefreshToken).To(Equal("rt"))
+
+		req := httpClient.DoArgsForCall(0)
+		Expect(req.URL.String()).To(Equal("https://example.my.salesforce.com/services/oauth2/token"))
+		body, _ := ioutil.ReadAll(req.Body)
+		form, _ := url.ParseQuery(string(body))
+		Expect(form).To(Equal(url.Values{
+			"grant_type":    {"authorization_code"},
+			"code":          {"code"},
+			"client_id":     {"id"},
+			"redirect_uri":  {"https://tool.example.com/callback"},
+			"code_verifier": {"verifier"},
+		}))
+
+		forceApi, err := force.New(
+			force.WithHttpClient(&httpClient),
+			force.WithTokenSource(flow.TokenSource(token)),
+			force.WithLazyMetadata(),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(httpClient.DoCallCount()).To(Equal(1))
+
+		Expect(forceApi.Query("SELECT Id FROM Account", &map[string]interface{}{})).To(Succeed())
+		req = httpClient.DoArgsForCall(2)
+		body, _ = ioutil.ReadAll(req.Body)
+		form, _ = url.ParseQuery(string(body))
+		Expect(form.Get("grant_type")).To(Equal("refresh_token"))
+		Expect(form.Get("refresh_token")).To(Equal("rt"))
+		Expect(httpClient.DoArgsForCall(3).Header.Get("Authorization")).To(Equal("Bearer renewed"))
+	})
+
+	It("should not refresh without a refresh token", func() {
+		_, err := flow.TokenSource(&force.Token{AccessToken: "at", InstanceUrl: "iu"}).Refresh(context.Background())
+		Expect(err).To(HaveOccurred())
+		Expect(httpClient.DoCallCount()).To(Equal(0))
+	})
+})
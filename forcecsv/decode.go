@@ -0,0 +1,200 @@
+package forcecsv
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Unmarshal parses CSV data whose first row holds the column names and
+// appends one element per remaining row to the slice pointed to by out.
+// The slice elements must be structs or struct pointers. Columns that don't
+// match a field are ignored, and empty values leave the field untouched.
+func Unmarshal(data []byte, out interface{}) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("forcecsv: cannot unmarshal into %T, expected a pointer to a slice", out)
+	}
+	slice := rv.Elem()
+	elemType := slice.Type().Elem()
+
+	dec := NewDecoder(bytes.NewReader(data))
+	for {
+		elem := reflect.New(elemType)
+		target := elem.Interface()
+		if elemType.Kind() == reflect.Ptr {
+			elem.Elem().Set(reflect.New(elemType.Elem()))
+			target = elem.Elem().Interface()
+		}
+
+		err := dec.Decode(target)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		slice.Set(reflect.Append(slice, elem.Elem()))
+	}
+}
+
+// A Decoder reads CSV rows one at a time and maps them onto structs.
+type Decoder struct {
+	r      *csv.Reader
+	header []string
+	row    []string
+}
+
+// NewDecoder returns a new decoder that reads from r. The first row read
+// from r is taken to be the header.
+func NewDecoder(r io.Reader) *Decoder {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	return &Decoder{r: cr}
+}
+
+// Header returns the column names, reading them from the input if needed.
+func (dec *Decoder) Header() ([]string, error) {
+	if dec.header != nil {
+		return dec.header, nil
+	}
+
+	header, err := dec.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	dec.header = header
+
+	return dec.header, nil
+}
+
+// Decode reads the next row and stores it in the struct pointed to by v.
+// It returns io.EOF when there are no more rows.
+func (dec *Decoder) Decode(v interface{}) error {
+	header, err := dec.Header()
+	if err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("forcecsv: cannot decode into %T, expected a pointer to a struct", v)
+	}
+
+	row, err := dec.r.Read()
+	if err != nil {
+		return err
+	}
+	dec.row = row
+
+	fields := cachedTypeFields(rv.Elem().Type())
+	for i, column := range header {
+		if i >= len(row) || row[i] == "" {
+			continue
+		}
+		f := lookupField(fields, column)
+		if f == nil {
+			continue
+		}
+		if err := setValue(allocFieldByIndex(rv.Elem(), f.index), row[i]); err != nil {
+			return fmt.Errorf("forcecsv: cannot decode %q into field %v: %v", row[i], column, err)
+		}
+	}
+
+	return nil
+}
+
+// Value returns the raw value of the named column in the row last read by
+// Decode. It is useful for columns that don't map onto the decoded struct,
+// like the sf__Id and sf__Error columns of Bulk API results.
+func (dec *Decoder) Value(column string) string {
+	for i, name := range dec.header {
+		if name == column && i < len(dec.row) {
+			return dec.row[i]
+		}
+	}
+	return ""
+}
+
+// lookupField finds the field for a column, preferring an exact match but
+// accepting a case-insensitive one, like forcejson does for object keys.
+func lookupField(fields []field, column string) *field {
+	var fold *field
+	for i := range fields {
+		f := &fields[i]
+		if f.name == column {
+			return f
+		}
+		if fold == nil && strings.EqualFold(f.name, column) {
+			fold = f
+		}
+	}
+	return fold
+}
+
+// allocFieldByIndex is like reflect.Value.FieldByIndex but allocates nil
+// struct pointers along the way.
+func allocFieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+func setValue(v reflect.Value, s string) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %v", v.Type())
+	}
+
+	return nil
+}
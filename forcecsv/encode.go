@@ -0,0 +1,255 @@
+// Package forcecsv converts between structs and the CSV documents used by the
+// force.com Bulk API. Columns map onto struct fields using the same `force`
+// struct tags that forcejson honors, so the SObject structs used with the REST
+// API work unchanged.
+package forcecsv
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// Marshal returns the CSV encoding of records, which must be a slice or
+// array (or a pointer to one) of structs, struct pointers or interfaces
+// holding them. All records must share the same type.
+//
+// The first row holds the column names. A field marked omitempty only gets a
+// column when at least one record has a non-empty value for it, so inserts
+// don't send read only fields like Id or CreatedDate. Nil pointers are
+// written as empty values, which the Bulk API treats as "leave unchanged".
+func Marshal(records interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).EncodeAll(records); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// An Encoder writes structs to CSV one row at a time.
+type Encoder struct {
+	w       *csv.Writer
+	typ     reflect.Type
+	columns []field
+	// omitted holds the omitempty fields left out of the header.
+	omitted []field
+}
+
+// NewEncoder returns a new encoder that writes to w. Rows are buffered;
+// call Flush, or use EncodeAll, to make sure they reach w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: csv.NewWriter(w)}
+}
+
+// Encode writes the row of record, a struct or struct pointer, preceded by
+// the header row on the first call. As the records to come are unknown, an
+// omitempty field only gets a column when the first record has a non-empty
+// value for it; Encode fails rather than drop the value of a later record.
+// All records must share the type of the first one.
+func (enc *Encoder) Encode(record interface{}) error {
+	row := indirect(reflect.ValueOf(record))
+	if !row.IsValid() || row.Kind() != reflect.Struct {
+		return fmt.Errorf("forcecsv: cannot marshal %T, expected a struct", record)
+	}
+	if enc.typ == nil {
+		if err := enc.writeHeader(row.Type(), []reflect.Value{row}); err != nil {
+			return err
+		}
+	}
+
+	return enc.writeRow(row)
+}
+
+// EncodeAll writes records like Marshal does, one row at a time, and flushes
+// them to the underlying writer.
+func (enc *Encoder) EncodeAll(records interface{}) error {
+	rv := reflect.ValueOf(records)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return fmt.Errorf("forcecsv: cannot marshal %T, expected a slice of structs", records)
+	}
+	if rv.Len() == 0 {
+		return fmt.Errorf("forcecsv: cannot marshal an empty slice")
+	}
+
+	rows := make([]reflect.Value, rv.Len())
+	typ := enc.typ
+	for i := range rows {
+		row := indirect(rv.Index(i))
+		if !row.IsValid() || row.Kind() != reflect.Struct {
+			return fmt.Errorf("forcecsv: record %d is not a struct", i)
+		}
+		if typ == nil {
+			typ = row.Type()
+		} else if row.Type() != typ {
+			return fmt.Errorf("forcecsv: record %d is a %v, expected %v", i, row.Type(), typ)
+		}
+		rows[i] = row
+	}
+
+	if enc.typ == nil {
+		if err := enc.writeHeader(typ, rows); err != nil {
+			return err
+		}
+	}
+	for _, row := range rows {
+		if err := enc.writeRow(row); err != nil {
+			return err
+		}
+	}
+
+	return enc.Flush()
+}
+
+// Flush writes any buffered rows to the underlying writer.
+func (enc *Encoder) Flush() error {
+	enc.w.Flush()
+	return enc.w.Error()
+}
+
+// writeHeader picks the columns of typ, leaving out omitempty fields that
+// are empty in all of rows, and writes their names.
+func (enc *Encoder) writeHeader(typ reflect.Type, rows []reflect.Value) error {
+	for _, f := range cachedTypeFields(typ) {
+		include := !f.omitEmpty
+		for _, row := range rows {
+			if include {
+				break
+			}
+			if fv, ok := fieldByIndex(row, f.index); ok && !isEmptyValue(fv) {
+				include = true
+			}
+		}
+		if include {
+			enc.columns = append(enc.columns, f)
+		} else {
+			enc.omitted = append(enc.omitted, f)
+		}
+	}
+	enc.typ = typ
+
+	header := make([]string, len(enc.columns))
+	for i, f := range enc.columns {
+		header[i] = f.name
+	}
+	return enc.w.Write(header)
+}
+
+func (enc *Encoder) writeRow(row reflect.Value) error {
+	if row.Type() != enc.typ {
+		return fmt.Errorf("forcecsv: cannot marshal a %v, expected %v", row.Type(), enc.typ)
+	}
+	for _, f := range enc.omitted {
+		if fv, ok := fieldByIndex(row, f.index); ok && !isEmptyValue(fv) {
+			return fmt.Errorf("forcecsv: cannot marshal field %v: it has no column", f.name)
+		}
+	}
+
+	record := make([]string, len(enc.columns))
+	for i, f := range enc.columns {
+		fv, ok := fieldByIndex(row, f.index)
+		if !ok {
+			continue
+		}
+		s, err := formatValue(fv)
+		if err != nil {
+			return fmt.Errorf("forcecsv: cannot marshal field %v: %v", f.name, err)
+		}
+		record[i] = s
+	}
+
+	return enc.w.Write(record)
+}
+
+// indirect follows pointers and interfaces until it reaches a concrete
+// value. It returns the zero Value for nil pointers.
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex but reports false instead
+// of panicking when it runs into a nil embedded pointer.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 {
+			if v.Kind() == reflect.Ptr {
+				if v.IsNil() {
+					return reflect.Value{}, false
+				}
+				v = v.Elem()
+			}
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+func formatValue(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		b, err := m.MarshalText()
+		return string(b), err
+	}
+	if v.CanAddr() {
+		if m, ok := v.Addr().Interface().(encoding.TextMarshaler); ok {
+			b, err := m.MarshalText()
+			return string(b), err
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	}
+
+	return "", fmt.Errorf("unsupported type %v", v.Type())
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	case reflect.Struct:
+		return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+package forcecsv
+
+import (
+	"encoding"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var (
+	textMarshalerType   = reflect.TypeOf(new(encoding.TextMarshaler)).Elem()
+	textUnmarshalerType = reflect.TypeOf(new(encoding.TextUnmarshaler)).Elem()
+)
+
+// A field is a single CSV column backed by a (possibly nested) struct field.
+type field struct {
+	name      string
+	index     []int
+	typ       reflect.Type
+	omitEmpty bool
+}
+
+var fieldCache struct {
+	sync.RWMutex
+	m map[reflect.Type][]field
+}
+
+// cachedTypeFields is like typeFields but uses a cache to avoid repeated work.
+func cachedTypeFields(t reflect.Type) []field {
+	fieldCache.RLock()
+	f := fieldCache.m[t]
+	fieldCache.RUnlock()
+	if f != nil {
+		return f
+	}
+
+	f = typeFields(t)
+
+	fieldCache.Lock()
+	if fieldCache.m == nil {
+		fieldCache.m = map[reflect.Type][]field{}
+	}
+	fieldCache.m[t] = f
+	fieldCache.Unlock()
+	return f
+}
+
+// typeFields returns the columns CSV should recognize for the given struct
+// type, in declaration order. Column names come from the `force` struct tag,
+// falling back to the field name, exactly like forcejson. Anonymous struct
+// fields are flattened and named struct fields become dotted relationship
+// columns such as "Account.Name". A field declared closer to the top level
+// hides promoted fields of the same name.
+func typeFields(t reflect.Type) []field {
+	fields := walkFields(t, "", nil, map[reflect.Type]bool{})
+
+	depth := map[string]int{}
+	for _, f := range fields {
+		if d, ok := depth[f.name]; !ok || len(f.index) < d {
+			depth[f.name] = len(f.index)
+		}
+	}
+
+	visible := fields[:0]
+	for _, f := range fields {
+		if len(f.index) == depth[f.name] {
+			visible = append(visible, f)
+			depth[f.name] = -1 // first one wins
+		}
+	}
+
+	return visible
+}
+
+// walkFields collects the columns of t. visiting holds the struct types on
+// the current path so self referencing types like a User with a Manager *User
+// don't recurse forever.
+func walkFields(t reflect.Type, prefix string, index []int, visiting map[reflect.Type]bool) []field {
+	if visiting[t] {
+		return nil
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	var fields []field
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous { // unexported
+			continue
+		}
+		tag := sf.Tag.Get("force")
+		if tag == "-" {
+			continue
+		}
+		name, opts := parseTag(tag)
+		if isAttributes(sf, name) {
+			continue
+		}
+
+		fieldIndex := make([]int, len(index)+1)
+		copy(fieldIndex, index)
+		fieldIndex[len(index)] = i
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		if isStructType(ft) {
+			if sf.Anonymous && name == "" {
+				fields = append(fields, walkFields(ft, prefix, fieldIndex, visiting)...)
+				continue
+			}
+			if name == "" {
+				name = sf.Name
+			}
+			fields = append(fields, walkFields(ft, prefix+name+".", fieldIndex, visiting)...)
+			continue
+		}
+
+		if !isScalarType(ft) {
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+		fields = append(fields, field{
+			name:      prefix + name,
+			index:     fieldIndex,
+			typ:       sf.Type,
+			omitEmpty: opts.Contains("omitempty"),
+		})
+	}
+
+	return fields
+}
+
+// isAttributes reports whether sf holds the attributes of a record read from
+// the REST API, such as its type and url. They describe the record rather
+// than being fields of it, and the Bulk API rejects them as unknown columns.
+func isAttributes(sf reflect.StructField, name string) bool {
+	if name == "" {
+		if sf.Anonymous {
+			return false
+		}
+		name = sf.Name
+	}
+	return strings.EqualFold(name, "attributes")
+}
+
+// isStructType reports whether t should be walked into rather than treated
+// as a single column.
+func isStructType(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	return !reflect.PtrTo(t).Implements(textMarshalerType) && !reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+func isScalarType(t reflect.Type) bool {
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) || t.Implements(textMarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// tagOptions is the string following a comma in a struct field's "force"
+// tag, or the empty string. It does not include the leading comma.
+type tagOptions string
+
+// parseTag splits a struct field's force tag into its name and
+// comma-separated options.
+func parseTag(tag string) (string, tagOptions) {
+	if idx := strings.Index(tag, ","); idx != -1 {
+		return tag[:idx], tagOptions(tag[idx+1:])
+	}
+	return tag, tagOptions("")
+}
+
+// Contains reports whether a comma-separated list of options
+// contains a particular flag.
+func (o tagOptions) Contains(optionName string) bool {
+	for _, opt := range strings.Split(string(o), ",") {
+		if opt == optionName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,13 @@
+package forcecsv_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestForcecsv(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Forcecsv Suite")
+}
@@ -0,0 +1,136 @@
+package forcecsv_test
+
+import (
+	"bytes"
+	"io"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/opendoor-labs/go-force/forcecsv"
+	"github.com/opendoor-labs/go-force/sobjects"
+)
+
+type Owner struct {
+	Name  string `force:",omitempty"`
+	Email string `force:"Email__c,omitempty"`
+}
+
+type Contact struct {
+	sobjects.BaseSObject
+	FirstName string  `force:",omitempty"`
+	Age       int     `force:"Age__c,omitempty"`
+	Score     float64 `force:"Score__c"`
+	Active    *bool   `force:"Active__c,omitempty"`
+	Owner     *Owner  `force:",omitempty"`
+	Ignored   string  `force:"-"`
+}
+
+var _ = Describe("Forcecsv", func() {
+	Describe("Marshal", func() {
+		It("should write a header and one row per record", func() {
+			active := false
+			records := []*Contact{
+				{FirstName: "Ada", Age: 36, Score: 1.5, Active: &active},
+				{FirstName: "Grace, B.", Score: 2},
+			}
+			records[0].Name = "Ada L"
+
+			data, err := forcecsv.Marshal(records)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal("Name,FirstName,Age__c,Score__c,Active__c\n" +
+				"Ada L,Ada,36,1.5,false\n" +
+				",\"Grace, B.\",0,2,\n"))
+		})
+
+		It("should write relationship columns for nested structs", func() {
+			records := []Contact{{Owner: &Owner{Email: "a@b.c"}}}
+
+			data, err := forcecsv.Marshal(records)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal("Score__c,Owner.Email__c\n0,a@b.c\n"))
+		})
+
+		It("should leave out the attributes of records read from the REST API", func() {
+			record := Contact{FirstName: "Ada"}
+			record.Attributes = sobjects.SObjectAttributes{Type: "Contact", Url: "/services/data/v52.0/sobjects/Contact/003A"}
+
+			data, err := forcecsv.Marshal([]Contact{record})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal("FirstName,Score__c\nAda,0\n"))
+		})
+
+		It("should reject records that aren't structs", func() {
+			_, err := forcecsv.Marshal([]string{"a"})
+			Expect(err).To(HaveOccurred())
+
+			_, err = forcecsv.Marshal([]Contact{})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Encoder", func() {
+		It("should write one row per record", func() {
+			var buf bytes.Buffer
+			enc := forcecsv.NewEncoder(&buf)
+
+			Expect(enc.Encode(&Contact{FirstName: "Ada"})).To(Succeed())
+			Expect(enc.Flush()).To(Succeed())
+			Expect(buf.String()).To(Equal("FirstName,Score__c\nAda,0\n"))
+
+			Expect(enc.Encode(Contact{FirstName: "Grace", Score: 2})).To(Succeed())
+			Expect(enc.Flush()).To(Succeed())
+			Expect(buf.String()).To(Equal("FirstName,Score__c\nAda,0\nGrace,2\n"))
+		})
+
+		It("should refuse values that have no column", func() {
+			enc := forcecsv.NewEncoder(&bytes.Buffer{})
+
+			Expect(enc.Encode(&Contact{FirstName: "Ada"})).To(Succeed())
+			Expect(enc.Encode(&Contact{FirstName: "Grace", Age: 85})).To(MatchError(ContainSubstring("Age__c")))
+			Expect(enc.Encode(&Owner{Name: "Ada"})).To(HaveOccurred())
+		})
+	})
+
+	Describe("Unmarshal", func() {
+		It("should decode rows into a slice of structs", func() {
+			data := []byte("Id,firstname,Age__c,Active__c,Owner.Name,Unknown\n" +
+				"003A,Ada,36,true,Bob,x\n" +
+				"003B,Grace,,,,\n")
+
+			var contacts []*Contact
+			err := forcecsv.Unmarshal(data, &contacts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contacts).To(HaveLen(2))
+
+			Expect(contacts[0].Id).To(Equal("003A"))
+			Expect(contacts[0].FirstName).To(Equal("Ada"))
+			Expect(contacts[0].Age).To(Equal(36))
+			Expect(*contacts[0].Active).To(BeTrue())
+			Expect(contacts[0].Owner.Name).To(Equal("Bob"))
+
+			Expect(contacts[1].Id).To(Equal("003B"))
+			Expect(contacts[1].Active).To(BeNil())
+			Expect(contacts[1].Owner).To(BeNil())
+		})
+
+		It("should report values that don't fit the field", func() {
+			var contacts []Contact
+			err := forcecsv.Unmarshal([]byte("Age__c\nold\n"), &contacts)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Decoder", func() {
+		It("should expose columns that don't map onto the struct", func() {
+			dec := forcecsv.NewDecoder(bytes.NewBufferString("\"sf__Id\",\"FirstName\"\n003A,Ada\n"))
+
+			contact := Contact{}
+			Expect(dec.Decode(&contact)).To(Succeed())
+			Expect(contact.FirstName).To(Equal("Ada"))
+			Expect(dec.Value("sf__Id")).To(Equal("003A"))
+
+			Expect(dec.Decode(&contact)).To(Equal(io.EOF))
+		})
+	})
+})